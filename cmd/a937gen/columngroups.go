@@ -0,0 +1,306 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gen"
+)
+
+// ColumnGroupConfig 编号列分组配置，如 t_rooms 的 user_id0..user_id8、user_name0..user_name8
+// 生成按组读写的辅助方法，原来的列和字段保持不变，读写数据库不受影响
+type ColumnGroupConfig struct {
+	Table  string `yaml:"table"`
+	Name   string `yaml:"name"`   // 元素名，如 Seat，生成 Seats() 和 SetSeat()
+	Prefix string `yaml:"prefix"` // 列名前缀，如 user_，默认为 name 的小写
+	Dims   int    `yaml:"dims"`   // 编号的维数，2 表示 open12 这样每位数字是一维，默认 1
+}
+
+func (c ColumnGroupConfig) prefix() string {
+	if c.Prefix != "" {
+		return strings.ToLower(c.Prefix)
+	}
+	return strings.ToLower(c.Name)
+}
+
+func (c ColumnGroupConfig) dims() int {
+	if c.Dims == 0 {
+		return 1
+	}
+	return c.Dims
+}
+
+// validate 检查分组配置
+func (c ColumnGroupConfig) validate() error {
+	if c.Table == "" || c.Name == "" {
+		return fmt.Errorf("列分组配置必须指定 table 和 name")
+	}
+	if c.dims() != 1 && c.dims() != 2 {
+		return fmt.Errorf("列分组 %s.%s 的 dims 只能是 1 或 2", c.Table, c.Name)
+	}
+	return nil
+}
+
+// modelField 模型中的一个字段
+type modelField struct {
+	Column string
+	Name   string
+	Type   string
+}
+
+// columnGroup 识别出的一组编号列
+type columnGroup struct {
+	Config  ColumnGroupConfig
+	Type    string     // 元素类型，单列分组为列的类型，多列分组为生成的结构体名
+	Members []string   // 组内的列名去掉前缀和编号后的部分，如 id、icon，按列的顺序排列；单列分组只有 ""
+	Fields  []string   // 元素结构体的字段名，与 Members 对应
+	Types   []string   // 元素结构体的字段类型，与 Members 对应
+	Rows    [][]int    // 编号，一维分组每行一个编号，二维分组每行为第一位数字相同的列的第二位数字
+	Cells   [][]string // 每个编号对应的模型字段名，按 Rows 的顺序排列，每项按 Members 排列
+}
+
+// single 判断是否为单列分组，如 open11..open42，元素直接使用列的类型
+func (g columnGroup) single() bool {
+	return len(g.Members) == 1 && g.Members[0] == ""
+}
+
+// groupFile 一个表识别出的列分组
+type groupFile struct {
+	FileName    string
+	ModelStruct string
+	Groups      []columnGroup
+}
+
+// columnGroups 识别表中配置的列分组，无法识别的分组打印警告后跳过
+func columnGroups(dbConfig DatabaseConfig, table, modelStruct string, fields []modelField) []columnGroup {
+	var groups []columnGroup
+	for _, groupConfig := range dbConfig.ColumnGroups {
+		if groupConfig.Table != table {
+			continue
+		}
+		group, err := detectColumnGroup(groupConfig, modelStruct, fields)
+		if err != nil {
+			fmt.Printf("警告: %s 的列分组 %s 无法生成: %v\n", table, groupConfig.Name, err)
+			continue
+		}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// fieldsOpt 返回记录表中字段最终类型的模型选项，必须放在所有修改字段类型的选项之后
+func fieldsOpt(fields *[]modelField) gen.ModelOpt {
+	return gen.FieldModify(func(f gen.Field) gen.Field {
+		*fields = append(*fields, modelField{
+			Column: f.ColumnName,
+			Name:   fieldNaming.SchemaName(f.ColumnName),
+			Type:   f.Type,
+		})
+		return f
+	})
+}
+
+// splitNumbered 把 "user_id0" 按前缀拆分为成员 "id" 和编号 "0"，不匹配时返回 false
+func splitNumbered(column, prefix string) (member, digits string, ok bool) {
+	lower := strings.ToLower(column)
+	if !strings.HasPrefix(lower, prefix) {
+		return "", "", false
+	}
+	rest := lower[len(prefix):]
+	end := len(rest)
+	for end > 0 && rest[end-1] >= '0' && rest[end-1] <= '9' {
+		end--
+	}
+	if end == len(rest) {
+		return "", "", false
+	}
+	return strings.Trim(rest[:end], "_"), rest[end:], true
+}
+
+// detectColumnGroup 在表的字段中识别分组，编号不完整或同一成员类型不一致时返回错误
+func detectColumnGroup(groupConfig ColumnGroupConfig, structName string, fields []modelField) (columnGroup, error) {
+	group := columnGroup{Config: groupConfig}
+	prefix := groupConfig.prefix()
+
+	// 编号 -> 成员 -> 字段
+	cells := make(map[string]map[string]modelField)
+	memberTypes := make(map[string]string)
+	for _, f := range fields {
+		member, digits, ok := splitNumbered(f.Column, prefix)
+		if !ok || (groupConfig.dims() == 2 && len(digits) != 2) {
+			continue
+		}
+		if t, ok := memberTypes[member]; !ok {
+			group.Members = append(group.Members, member)
+		} else if t != f.Type {
+			return group, fmt.Errorf("列 %s 的类型 %s 与同组的 %s 不同", f.Column, f.Type, t)
+		}
+		memberTypes[member] = f.Type
+		if cells[digits] == nil {
+			cells[digits] = make(map[string]modelField)
+		}
+		cells[digits][member] = f
+	}
+	if len(cells) < 2 {
+		return group, fmt.Errorf("没有找到前缀为 %s 的编号列", prefix)
+	}
+
+	// 成员按列在表中的顺序排列
+	for _, member := range group.Members {
+		group.Fields = append(group.Fields, fieldNaming.SchemaName(member))
+		group.Types = append(group.Types, memberTypes[member])
+	}
+	if group.single() {
+		group.Type = memberTypes[""]
+	} else {
+		group.Type = structName + groupConfig.Name
+	}
+
+	keys := make([]string, 0, len(cells))
+	for digits, members := range cells {
+		if len(members) != len(group.Members) {
+			return group, fmt.Errorf("编号 %s 的列不完整", digits)
+		}
+		keys = append(keys, digits)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, _ := strconv.Atoi(keys[i])
+		b, _ := strconv.Atoi(keys[j])
+		return a < b
+	})
+
+	lastRow := -1
+	for _, digits := range keys {
+		var names []string
+		for _, member := range group.Members {
+			names = append(names, cells[digits][member].Name)
+		}
+		group.Cells = append(group.Cells, names)
+
+		if groupConfig.dims() == 1 {
+			n, _ := strconv.Atoi(digits)
+			group.Rows = append(group.Rows, []int{n})
+			continue
+		}
+		row, col := int(digits[0]-'0'), int(digits[1]-'0')
+		if row != lastRow {
+			group.Rows = append(group.Rows, nil)
+			lastRow = row
+		}
+		group.Rows[len(group.Rows)-1] = append(group.Rows[len(group.Rows)-1], col)
+	}
+	return group, nil
+}
+
+// element 返回第 cell 个编号对应的元素表达式，如 TRoomSeat{ID: m.UserId0, ...}
+func (g columnGroup) element(cell int) string {
+	names := g.Cells[cell]
+	if g.single() {
+		return "m." + names[0]
+	}
+	var parts []string
+	for i, name := range names {
+		parts = append(parts, fmt.Sprintf("%s: m.%s", g.Fields[i], name))
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// assign 返回把 value 写回第 cell 个编号对应字段的语句
+func (g columnGroup) assign(cell int) string {
+	names := g.Cells[cell]
+	if g.single() {
+		return "m." + names[0] + " = value"
+	}
+	var left, right []string
+	for i, name := range names {
+		left = append(left, "m."+name)
+		right = append(right, "value."+g.Fields[i])
+	}
+	return strings.Join(left, ", ") + " = " + strings.Join(right, ", ")
+}
+
+// generateColumnGroupFile 生成表的编号列分组辅助方法，写入 <model_pkg_path>/<file>_groups.gen.go
+func generateColumnGroupFile(dbConfig DatabaseConfig, fileName, modelStruct string, groups []columnGroup) error {
+	if len(groups) == 0 {
+		return nil
+	}
+	modelPkg := filepath.Base(filepath.Clean(dbConfig.modelPath()))
+
+	var code strings.Builder
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n")
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n")
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n\n")
+	code.WriteString(fmt.Sprintf("package %s\n\n", modelPkg))
+	code.WriteString(fmt.Sprintf("import (\n\t\"fmt\"\n\n\t%q\n)\n", typesPkgPath))
+
+	for _, g := range groups {
+		name := g.Config.Name
+		if !g.single() {
+			code.WriteString(fmt.Sprintf("\n// %s %s 中按编号分组的 %s* 列\n", g.Type, g.Config.Table, g.Config.prefix()))
+			code.WriteString(fmt.Sprintf("type %s struct {\n", g.Type))
+			for i, field := range g.Fields {
+				code.WriteString(fmt.Sprintf("\t%s %s `json:\"%s\"`\n", field, g.Types[i], g.Members[i]))
+			}
+			code.WriteString("}\n")
+		}
+
+		cell := 0
+		if g.Config.dims() == 1 {
+			code.WriteString(fmt.Sprintf("\n// %ss 返回编号 %d 到 %d 的 %s 列组成的列表\n", name, g.Rows[0][0], g.Rows[len(g.Rows)-1][0], g.Config.prefix()))
+			code.WriteString(fmt.Sprintf("func (m *%s) %ss() []%s {\n", modelStruct, name, g.Type))
+			code.WriteString(fmt.Sprintf("\treturn []%s{\n", g.Type))
+			for i := range g.Rows {
+				code.WriteString(fmt.Sprintf("\t\t%s,\n", g.element(i)))
+			}
+			code.WriteString("\t}\n}\n")
+
+			code.WriteString(fmt.Sprintf("\n// Set%s 把 value 写回 %ss() 中下标为 i 的元素对应的列\n", name, name))
+			code.WriteString(fmt.Sprintf("func (m *%s) Set%s(i int, value %s) {\n", modelStruct, name, g.Type))
+			code.WriteString("\tswitch i {\n")
+			for i := range g.Rows {
+				code.WriteString(fmt.Sprintf("\tcase %d:\n\t\t%s\n", i, g.assign(i)))
+			}
+			code.WriteString("\tdefault:\n")
+			code.WriteString(fmt.Sprintf("\t\tpanic(fmt.Sprintf(\"%s.Set%s: 下标 %%d 超出范围 [0, %d)\", i))\n", modelStruct, name, len(g.Rows)))
+			code.WriteString("\t}\n}\n")
+			continue
+		}
+
+		code.WriteString(fmt.Sprintf("\n// %ss 返回 %s 列按两位编号组成的二维列表，第一位数字相同的列为一行\n", name, g.Config.prefix()))
+		code.WriteString(fmt.Sprintf("func (m *%s) %ss() [][]%s {\n", modelStruct, name, g.Type))
+		code.WriteString(fmt.Sprintf("\treturn [][]%s{\n", g.Type))
+		for _, row := range g.Rows {
+			var elements []string
+			for range row {
+				elements = append(elements, g.element(cell))
+				cell++
+			}
+			code.WriteString(fmt.Sprintf("\t\t{%s},\n", strings.Join(elements, ", ")))
+		}
+		code.WriteString("\t}\n}\n")
+
+		code.WriteString(fmt.Sprintf("\n// Set%s 把 value 写回 %ss()[i][j] 对应的列\n", name, name))
+		code.WriteString(fmt.Sprintf("func (m *%s) Set%s(i, j int, value %s) {\n", modelStruct, name, g.Type))
+		code.WriteString("\tswitch [2]int{i, j} {\n")
+		cell = 0
+		for i, row := range g.Rows {
+			for j := range row {
+				code.WriteString(fmt.Sprintf("\tcase [2]int{%d, %d}:\n\t\t%s\n", i, j, g.assign(cell)))
+				cell++
+			}
+		}
+		code.WriteString("\tdefault:\n")
+		code.WriteString(fmt.Sprintf("\t\tpanic(fmt.Sprintf(\"%s.Set%s: 下标 [%%d][%%d] 超出范围\", i, j))\n", modelStruct, name))
+		code.WriteString("\t}\n}\n")
+	}
+
+	filePath := filepath.Join(dbConfig.modelPath(), fileName+"_groups.gen.go")
+	if err := writeGoFileImports(filePath, code.String()); err != nil {
+		return err
+	}
+	fmt.Printf("生成列分组方法: %s\n", filePath)
+	return nil
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+)
+
+// commentEncodings 可能把 UTF-8 注释解码成乱码的字符集，使用 MySQL 的字符集名
+var commentEncodings = map[string]encoding.Encoding{
+	"gbk":     simplifiedchinese.GBK,
+	"gb2312":  simplifiedchinese.GBK,
+	"gb18030": simplifiedchinese.GB18030,
+	"big5":    traditionalchinese.Big5,
+	"latin1":  charmap.Windows1252, // MySQL 的 latin1 实际是 cp1252
+}
+
+// isUTF8Charset 判断是否为不需要转码的 UTF-8 字符集
+func isUTF8Charset(charset string) bool {
+	charset = strings.ToLower(charset)
+	return charset == "" || charset == "utf8" || charset == "utf8mb3" || charset == "utf8mb4"
+}
+
+// validCommentCharset 检查 comment_charset 配置
+func validCommentCharset(charset string) error {
+	if isUTF8Charset(charset) || commentEncodings[strings.ToLower(charset)] != nil {
+		return nil
+	}
+	return fmt.Errorf("不支持的 comment_charset %q，可用: utf8mb4、gbk、gb18030、big5、latin1", charset)
+}
+
+// fixComment 修复以 charset 声明的列读出的乱码注释
+// 注释原本是 UTF-8 字节，却被当作 charset 解码后再转成了 UTF-8（如 "用户ID" 变成 "鐢ㄦ埛ID"），
+// 这里按 charset 重新编码还原出原始字节。很多正确的中文按 GBK 编码后碰巧也是合法的 UTF-8（如 "状态" 得到 "״̬"），
+// 因此只有还原结果比原注释更像正常的中文注释时才采用，否则原样返回。
+func fixComment(comment, charset string) string {
+	if isUTF8Charset(charset) || isASCII(comment) {
+		return comment
+	}
+	enc := commentEncodings[strings.ToLower(charset)]
+	if enc == nil {
+		return comment
+	}
+
+	raw, err := enc.NewEncoder().String(comment)
+	if err != nil || raw == comment || !utf8.ValidString(raw) || !plausibleComment(raw) {
+		return comment
+	}
+	// 原注释含有拉丁字母、西里尔字母、注音符号等中文注释中不会出现的字符时一定是乱码；
+	// 全是汉字时（如 "鍒涘缓鏃堕棿"）比较常用字的比例，乱码由 GBK 二级字库和扩展区的生僻字组成
+	if plausibleComment(comment) && commonRatio(raw) <= commonRatio(comment) {
+		return comment
+	}
+	return raw
+}
+
+// plausibleComment 判断注释是否只含 ASCII、汉字和中文注释中常见的标点
+func plausibleComment(s string) bool {
+	for _, r := range s {
+		switch {
+		case r < utf8.RuneSelf,
+			r >= 0x4E00 && r <= 0x9FFF,                // CJK 统一汉字
+			r >= 0x3000 && r <= 0x303F,                // CJK 标点，如 、。《》【】
+			r >= 0xFF01 && r <= 0xFF5E,                // 全角 ASCII，如 （）：，
+			r >= 0x2010 && r <= 0x2027,                // 破折号、引号、省略号
+			r == 0x00B7 || r == 0x00D7 || r == 0x00F7: // · × ÷
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// commonRatio 返回非 ASCII 字符中 GB2312 一级常用汉字（GBK 编码首字节 0xB0-0xD7）的比例
+func commonRatio(s string) float64 {
+	encoder := simplifiedchinese.GBK.NewEncoder()
+	total, common := 0, 0
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			continue
+		}
+		total++
+		if b, err := encoder.Bytes([]byte(string(r))); err == nil && len(b) == 2 && b[0] >= 0xB0 && b[0] <= 0xD7 {
+			common++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(common) / float64(total)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// columnComments 查询 schema 中的列注释，返回 表名 -> 列名 -> 修复后的注释，只包含需要修复的列
+// override 为 comment_charset 配置，非空时忽略 information_schema 中声明的字符集
+func columnComments(db *gorm.DB, schema, override string) (map[string]map[string]string, error) {
+	rows, err := commentRows(db, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make(map[string]map[string]string)
+	for _, row := range rows {
+		// 列没有字符集（如数字列）时使用表的字符集，即表排序规则的前缀
+		charset := override
+		if charset == "" {
+			charset = row.Charset
+		}
+		if charset == "" {
+			charset = strings.SplitN(row.TableCollation, "_", 2)[0]
+		}
+
+		fixed := fixComment(row.Comment, charset)
+		if fixed == row.Comment {
+			continue
+		}
+		if comments[row.Table] == nil {
+			comments[row.Table] = make(map[string]string)
+		}
+		comments[row.Table][row.Column] = fixed
+	}
+	return comments, nil
+}
+
+// commentRow 有注释的列
+type commentRow struct {
+	Table, Column  string
+	Charset        string // 列的字符集，数字列等为空
+	TableCollation string
+	Comment        string
+}
+
+// commentRows 返回 schema 中有注释的列，使用快照时从快照读取
+func commentRows(db *gorm.DB, schema string) ([]commentRow, error) {
+	if snapshot := snapshotOf(db); snapshot != nil {
+		var result []commentRow
+		for _, table := range snapshot.Tables {
+			for _, column := range table.Columns {
+				if column.Comment != "" {
+					result = append(result, commentRow{table.Name, column.Name, column.Charset, table.Collation, column.Comment})
+				}
+			}
+		}
+		return result, nil
+	}
+
+	query := `
+		SELECT
+			c.TABLE_NAME,
+			c.COLUMN_NAME,
+			COALESCE(c.CHARACTER_SET_NAME, ''),
+			COALESCE(t.TABLE_COLLATION, ''),
+			c.COLUMN_COMMENT
+		FROM information_schema.COLUMNS c
+		JOIN information_schema.TABLES t
+			ON t.TABLE_SCHEMA = c.TABLE_SCHEMA AND t.TABLE_NAME = c.TABLE_NAME
+		WHERE c.TABLE_SCHEMA = ? AND c.COLUMN_COMMENT <> ''
+	`
+
+	rows, err := db.Raw(query, schema).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("查询列注释失败: %v", err)
+	}
+	defer rows.Close()
+
+	var result []commentRow
+	for rows.Next() {
+		var row commentRow
+		if err := rows.Scan(&row.Table, &row.Column, &row.Charset, &row.TableCollation, &row.Comment); err != nil {
+			return nil, fmt.Errorf("读取列注释失败: %v", err)
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取列注释失败: %v", err)
+	}
+	return result, nil
+}
+
+// commentOpts 返回替换表中乱码注释的模型选项，同时修改结构体注释和 gorm 的 comment 标签
+func commentOpts(comments map[string]string) []gen.ModelOpt {
+	var opts []gen.ModelOpt
+	for column, comment := range comments {
+		comment := comment
+		opts = append(opts,
+			gen.FieldComment(column, comment),
+			gen.FieldGORMTag(column, func(tag field.GormTag) field.GormTag {
+				return tag.Set(field.TagKeyGormComment, strings.ReplaceAll(comment, "\n", "\\n"))
+			}),
+		)
+	}
+	return opts
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestFixComment(t *testing.T) {
+	tests := []struct {
+		comment, charset, want string
+	}{
+		// UTF-8 注释被当作 gbk/latin1 解码后的乱码
+		{"鐢ㄦ埛ID", "gbk", "用户ID"},
+		{"鐢ㄦ埛ID", "GBK", "用户ID"},
+		{"鍚嶇О", "gbk", "名称"},
+		{"鍒涘缓鏃堕棿", "gbk", "创建时间"},
+		{"閲戝竵", "gb2312", "金币"},
+		{"ç”¨æˆ·ID", "latin1", "用户ID"},
+		{"åˆ›å»ºæ—¶é—´", "latin1", "创建时间"},
+		{"å…ƒ", "latin1", "元"},
+
+		// 本身正确的注释原样返回
+		{"状态", "gbk", "状态"},
+		{"元", "gbk", "元"},
+		{"小盲注", "gbk", "小盲注"},
+		{"房间号", "gbk", "房间号"},
+		{"是否在线(0:否 1:是)", "gbk", "是否在线(0:否 1:是)"},
+		{"用户ID", "gbk", "用户ID"},
+		{"创建时间", "gbk", "创建时间"},
+		{"状态", "latin1", "状态"},
+		{"user id", "gbk", "user id"},
+		{"鐢ㄦ埛ID", "utf8mb4", "鐢ㄦ埛ID"},
+		{"鐢ㄦ埛ID", "", "鐢ㄦ埛ID"},
+		{"鐢ㄦ埛ID", "cp1250", "鐢ㄦ埛ID"},
+	}
+	for _, tt := range tests {
+		if got := fixComment(tt.comment, tt.charset); got != tt.want {
+			t.Errorf("fixComment(%q, %q) = %q, want %q", tt.comment, tt.charset, got, tt.want)
+		}
+	}
+}
+
+// TestFixCommentModels 现有模型中的注释都是正确的，任何字符集下都不应被改写
+func TestFixCommentModels(t *testing.T) {
+	files, err := filepath.Glob("../../models/*/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag := regexp.MustCompile(`comment:([^;"]+)`)
+	count := 0
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range tag.FindAllStringSubmatch(string(data), -1) {
+			comment := match[1]
+			if isASCII(comment) {
+				continue
+			}
+			count++
+			for charset := range commentEncodings {
+				if got := fixComment(comment, charset); got != comment {
+					t.Errorf("%s: fixComment(%q, %q) = %q", filepath.Base(file), comment, charset, got)
+				}
+			}
+		}
+	}
+	if count == 0 {
+		t.Skip("models 中没有中文注释")
+	}
+}
@@ -0,0 +1,395 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gopkg.in/yaml.v2"
+	"gorm.io/gen"
+)
+
+// ResultConfig 存储过程结果集配置
+type ResultConfig struct {
+	Columns    []ResultColumn `yaml:"columns"`     // 显式声明的结果列（单结果集）
+	ResultSets []ResultSet    `yaml:"result_sets"` // 显式声明的多个结果集，按返回顺序排列
+	Probe      bool           `yaml:"probe"`       // 在回滚的事务中试调用存储过程以探测结果列
+	Args       []string       `yaml:"args"`        // 试调用时按顺序传入的 IN 参数，缺省为 NULL
+}
+
+// DatabaseConfig 数据库配置，models 与 procedures 子命令共用
+type DatabaseConfig struct {
+	Name         string                  `yaml:"name"`
+	DSN          string                  `yaml:"dsn"`
+	Snapshot     string                  `yaml:"snapshot"` // 表结构快照文件（.json）或 mysqldump 导出的表结构（.sql），设置后不连接数据库
+	Schema       string                  `yaml:"schema"`   // 实际的 MySQL schema 名，缺省从 DSN 或快照中读取
+	OutPath      string                  `yaml:"out_path"`
+	ModelPkgPath string                  `yaml:"model_pkg_path"` // 模型包目录，默认 <out_path>/model
+	Tables       []string                `yaml:"tables"`         // 需要生成模型的表，为空表示所有表
+	Procedures   []string                `yaml:"procedures"`     // 需要生成包装方法的存储过程，为空表示所有
+	ProbeResults bool                    `yaml:"probe_results"`  // 对所有未声明结果列的存储过程进行试调用
+	Results      map[string]ResultConfig `yaml:"results"`        // 存储过程名 -> 结果集配置
+	Shards       []ShardConfig           `yaml:"shards"`         // 合并为一个模型的分表
+	Enums        []EnumConfig            `yaml:"enums"`          // 枚举列配置，覆盖从注释中解析的结果
+	ColumnTypes  map[string]string       `yaml:"column_types"`   // 表名.列名 -> Go 类型，覆盖默认的类型映射
+	ColumnGroups []ColumnGroupConfig     `yaml:"column_groups"`  // 生成按组读写辅助方法的编号列
+	Relations    []RelationConfig        `yaml:"relations"`      // 表之间的关联，生成 belongs-to/has-many/has-one 字段
+	// 存储 JSON 文本的列，"表名.列名" -> Go 类型，生成 types.JSON 字段和 JSON 查询方法，表名和列名支持通配符
+	JSONColumns map[string]string `yaml:"json_columns"`
+	// 存储 Unix 时间戳的列，"表名.列名" 或 "列名"，支持通配符，与 global 中的配置合并
+	UnixTimeColumns []string `yaml:"unix_time_columns"`
+	// 映射为 types.Decimal 的 DECIMAL/浮点列，格式同 unix_time_columns，与 global 中的配置合并
+	DecimalColumns []string `yaml:"decimal_columns"`
+	// 列注释的字符集，缺省按 information_schema 中声明的字符集修复乱码注释，utf8mb4 表示不修复
+	CommentCharset string `yaml:"comment_charset"`
+
+	GenOptions `yaml:",inline"` // 覆盖 global 中的生成选项
+}
+
+// modelPath 返回数据库模型包的输出目录
+func (c DatabaseConfig) modelPath() string {
+	if c.ModelPkgPath != "" {
+		return c.ModelPkgPath
+	}
+	return filepath.Join(c.OutPath, "model")
+}
+
+// schemaName 返回查询 information_schema 时使用的 schema 名
+// Name 只是配置中的逻辑名称（如 GAMEACCOUNT），不能用作 schema
+func (c DatabaseConfig) schemaName() (string, error) {
+	if c.Schema != "" {
+		return c.Schema, nil
+	}
+	dsnConfig, err := mysqldriver.ParseDSN(c.DSN)
+	if err != nil {
+		return "", fmt.Errorf("解析 DSN 失败: %v", err)
+	}
+	if dsnConfig.DBName == "" {
+		return "", fmt.Errorf("DSN 中没有指定数据库，请配置 schema")
+	}
+	return dsnConfig.DBName, nil
+}
+
+// packageName 返回生成代码的包名，与 gorm/gen 一致取输出目录的最后一级
+func (c DatabaseConfig) packageName() string {
+	return filepath.Base(filepath.Clean(c.OutPath))
+}
+
+// interfaceName 返回生成的存储过程接口名，如 GameaccountProcedures
+func (c DatabaseConfig) interfaceName() string {
+	return strings.Title(c.packageName()) + "Procedures"
+}
+
+// GenOptions gorm/gen 生成选项，global 中设置默认值，数据库中设置的项覆盖默认值
+type GenOptions struct {
+	Mode              *string `yaml:"mode"`                 // 生成模式，如 without_context|with_default_query
+	OutFile           *string `yaml:"out_file"`             // 查询代码文件名，默认 gen.go
+	WithUnitTest      *bool   `yaml:"with_unit_test"`       // 为查询代码生成单元测试
+	FieldNullable     *bool   `yaml:"field_nullable"`       // 可空字段生成指针
+	FieldCoverable    *bool   `yaml:"field_coverable"`      // 有默认值的字段生成指针
+	FieldSignable     *bool   `yaml:"field_signable"`       // 识别无符号整数类型
+	FieldWithIndexTag *bool   `yaml:"field_with_index_tag"` // 生成 gorm 索引标签
+	FieldWithTypeTag  *bool   `yaml:"field_with_type_tag"`  // 生成 gorm 列类型标签
+	Decimal           *bool   `yaml:"decimal"`              // 所有 DECIMAL 列映射为 types.Decimal 而不是 float64
+	DiscoverRelations *bool   `yaml:"discover_relations"`   // 从外键约束中发现关联，与 relations 合并
+}
+
+// defaultMode 未配置 mode 时使用的生成模式
+const defaultMode = "without_context|with_default_query|with_query_interface"
+
+// generateModes mode 配置中可用的选项
+var generateModes = map[string]gen.GenerateMode{
+	"with_default_query":   gen.WithDefaultQuery,
+	"without_context":      gen.WithoutContext,
+	"with_query_interface": gen.WithQueryInterface,
+}
+
+// parseMode 将 "without_context|with_default_query" 形式的配置解析为 gen.GenerateMode
+func parseMode(mode string) (gen.GenerateMode, error) {
+	var result gen.GenerateMode
+	for _, name := range strings.Split(mode, "|") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		flag, ok := generateModes[strings.ToLower(name)]
+		if !ok {
+			return 0, fmt.Errorf("未知的生成模式 %q，可用: with_default_query、without_context、with_query_interface", name)
+		}
+		result |= flag
+	}
+	return result, nil
+}
+
+// merge 返回以 o 为默认值、override 中设置的项覆盖后的选项
+func (o GenOptions) merge(override GenOptions) GenOptions {
+	if override.Mode != nil {
+		o.Mode = override.Mode
+	}
+	if override.OutFile != nil {
+		o.OutFile = override.OutFile
+	}
+	if override.WithUnitTest != nil {
+		o.WithUnitTest = override.WithUnitTest
+	}
+	if override.FieldNullable != nil {
+		o.FieldNullable = override.FieldNullable
+	}
+	if override.FieldCoverable != nil {
+		o.FieldCoverable = override.FieldCoverable
+	}
+	if override.FieldSignable != nil {
+		o.FieldSignable = override.FieldSignable
+	}
+	if override.FieldWithIndexTag != nil {
+		o.FieldWithIndexTag = override.FieldWithIndexTag
+	}
+	if override.FieldWithTypeTag != nil {
+		o.FieldWithTypeTag = override.FieldWithTypeTag
+	}
+	if override.Decimal != nil {
+		o.Decimal = override.Decimal
+	}
+	if override.DiscoverRelations != nil {
+		o.DiscoverRelations = override.DiscoverRelations
+	}
+	return o
+}
+
+// genConfig 将生成选项转换为 gen.Config，输出路径由调用方设置
+func (o GenOptions) genConfig() (gen.Config, error) {
+	mode := defaultMode
+	if o.Mode != nil {
+		mode = *o.Mode
+	}
+	generateMode, err := parseMode(mode)
+	if err != nil {
+		return gen.Config{}, err
+	}
+
+	return gen.Config{
+		OutFile:      stringValue(o.OutFile),
+		WithUnitTest: boolValue(o.WithUnitTest),
+		Mode:         generateMode,
+
+		// 字段配置
+		FieldNullable:     boolValue(o.FieldNullable),
+		FieldCoverable:    boolValue(o.FieldCoverable),
+		FieldSignable:     boolValue(o.FieldSignable),
+		FieldWithIndexTag: boolValue(o.FieldWithIndexTag),
+		FieldWithTypeTag:  boolValue(o.FieldWithTypeTag),
+	}, nil
+}
+
+func boolValue(b *bool) bool {
+	return b != nil && *b
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// GlobalConfig 全局配置
+type GlobalConfig struct {
+	GenOptions `yaml:",inline"`
+
+	UnixTimeColumns []string `yaml:"unix_time_columns"` // 所有数据库共用的 Unix 时间戳列
+	DecimalColumns  []string `yaml:"decimal_columns"`   // 所有数据库共用的 types.Decimal 列
+}
+
+// Config 完整配置
+type Config struct {
+	Databases []DatabaseConfig `yaml:"databases"`
+	Global    GlobalConfig     `yaml:"global"`
+}
+
+// renamedKeys 已经改名的配置项，旧名称 -> 新名称
+var renamedKeys = map[string]string{
+	"field_with_null_tag": "field_nullable",
+}
+
+// loadConfig 加载配置文件，未知的配置项会报错
+func loadConfig(filename string) (*Config, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	err = yaml.UnmarshalStrict(data, &config)
+	if err != nil {
+		msg := err.Error()
+		for oldKey, newKey := range renamedKeys {
+			if strings.Contains(msg, "field "+oldKey+" not found") {
+				msg += fmt.Sprintf("\n  配置项 %s 已改名为 %s", oldKey, newKey)
+			}
+		}
+		return nil, fmt.Errorf("解析 %s 失败: %s", filename, msg)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	return &config, nil
+}
+
+// validate 检查配置中无法在解析时发现的错误
+func (c *Config) validate() error {
+	names := make(map[string]bool)
+	for i, dbConfig := range c.Databases {
+		if dbConfig.Name == "" {
+			return fmt.Errorf("第 %d 个数据库没有配置 name", i+1)
+		}
+		if names[strings.ToUpper(dbConfig.Name)] {
+			return fmt.Errorf("数据库 %s 重复配置", dbConfig.Name)
+		}
+		names[strings.ToUpper(dbConfig.Name)] = true
+
+		if _, err := c.Global.optionsFor(dbConfig).genConfig(); err != nil {
+			return fmt.Errorf("数据库 %s: %v", dbConfig.Name, err)
+		}
+		if err := validCommentCharset(dbConfig.CommentCharset); err != nil {
+			return fmt.Errorf("数据库 %s: %v", dbConfig.Name, err)
+		}
+		for _, pattern := range c.Global.unixTimeColumns(dbConfig) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("数据库 %s: unix_time_columns 中的 %q 不是合法的模式", dbConfig.Name, pattern)
+			}
+		}
+		for _, pattern := range c.Global.decimalColumns(dbConfig) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("数据库 %s: decimal_columns 中的 %q 不是合法的模式", dbConfig.Name, pattern)
+			}
+		}
+		for key := range dbConfig.ColumnTypes {
+			if err := validColumnKey(key); err != nil {
+				return fmt.Errorf("数据库 %s: %v", dbConfig.Name, err)
+			}
+		}
+		for key, goType := range dbConfig.JSONColumns {
+			if err := validColumnKey(key); err != nil {
+				return fmt.Errorf("数据库 %s: %v", dbConfig.Name, err)
+			}
+			if _, err := path.Match(key, ""); err != nil {
+				return fmt.Errorf("数据库 %s: json_columns 中的 %q 不是合法的模式", dbConfig.Name, key)
+			}
+			if strings.TrimSpace(goType) == "" {
+				return fmt.Errorf("数据库 %s: json_columns 中的 %s 没有指定 Go 类型", dbConfig.Name, key)
+			}
+		}
+		for _, enumConfig := range dbConfig.Enums {
+			if enumConfig.Table == "" || enumConfig.Column == "" {
+				return fmt.Errorf("数据库 %s: 枚举配置必须指定 table 和 column", dbConfig.Name)
+			}
+		}
+		for _, groupConfig := range dbConfig.ColumnGroups {
+			if err := groupConfig.validate(); err != nil {
+				return fmt.Errorf("数据库 %s: %v", dbConfig.Name, err)
+			}
+		}
+		for _, relationConfig := range dbConfig.Relations {
+			if err := relationConfig.validate(); err != nil {
+				return fmt.Errorf("数据库 %s: %v", dbConfig.Name, err)
+			}
+		}
+		for _, shardConfig := range dbConfig.Shards {
+			if _, err := shardConfig.regexp(); err != nil {
+				return fmt.Errorf("数据库 %s: %v", dbConfig.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// optionsFor 返回数据库合并全局配置后的生成选项
+func (g GlobalConfig) optionsFor(dbConfig DatabaseConfig) GenOptions {
+	return g.GenOptions.merge(dbConfig.GenOptions)
+}
+
+// unixTimeColumns 返回数据库合并全局配置后的 Unix 时间戳列
+func (g GlobalConfig) unixTimeColumns(dbConfig DatabaseConfig) []string {
+	return append(append([]string{}, g.UnixTimeColumns...), dbConfig.UnixTimeColumns...)
+}
+
+// decimalColumns 返回数据库合并全局配置后的 types.Decimal 列
+func (g GlobalConfig) decimalColumns(dbConfig DatabaseConfig) []string {
+	return append(append([]string{}, g.DecimalColumns...), dbConfig.DecimalColumns...)
+}
+
+// selectDatabases 按 -db 参数筛选数据库，names 为逗号分隔的数据库名，不区分大小写
+func (c *Config) selectDatabases(names string) ([]DatabaseConfig, error) {
+	if names == "" {
+		return c.Databases, nil
+	}
+
+	var selected []DatabaseConfig
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, dbConfig := range c.Databases {
+			if strings.EqualFold(dbConfig.Name, name) {
+				selected = append(selected, dbConfig)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("配置中没有名为 %s 的数据库", name)
+		}
+	}
+	return selected, nil
+}
+
+// databasesFromEnv 从 DB_DSN_<NAME> / DB_TABLES_<NAME> 环境变量构造数据库配置
+// names 为空时使用所有设置了 DB_DSN_ 的数据库
+func databasesFromEnv(names []string) ([]DatabaseConfig, error) {
+	if len(names) == 0 {
+		for _, env := range os.Environ() {
+			if strings.HasPrefix(env, "DB_DSN_") {
+				names = append(names, strings.SplitN(strings.TrimPrefix(env, "DB_DSN_"), "=", 2)[0])
+			}
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("未找到任何数据库配置，请设置 DB_DSN_<NAME> 环境变量")
+		}
+	}
+
+	var databases []DatabaseConfig
+	for _, name := range names {
+		name = strings.ToUpper(name)
+		dsnEnv := fmt.Sprintf("DB_DSN_%s", name)
+		dsn := os.Getenv(dsnEnv)
+		if dsn == "" {
+			return nil, fmt.Errorf("未找到数据库 %s 的连接配置，请设置环境变量 %s", name, dsnEnv)
+		}
+
+		var tables []string
+		if tablesStr := os.Getenv(fmt.Sprintf("DB_TABLES_%s", name)); tablesStr != "" {
+			for _, table := range strings.Split(tablesStr, ",") {
+				tables = append(tables, strings.TrimSpace(table))
+			}
+		}
+
+		databases = append(databases, DatabaseConfig{
+			Name:    name,
+			DSN:     dsn,
+			OutPath: fmt.Sprintf("./models/%s", strings.ToLower(name)),
+			Tables:  tables,
+		})
+	}
+	return databases, nil
+}
@@ -0,0 +1,1140 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// 从 mysqldump --no-data 导出的 SQL 文件中读取表结构
+// CREATE TABLE、CREATE PROCEDURE/FUNCTION 被解析为与 information_schema 相同的快照，之后的生成流程与连接数据库时相同
+
+// sqlToken DDL 中的一个词法单元
+type sqlToken struct {
+	Kind byte   // 'w' 单词或数字，'q' 反引号标识符，'s' 字符串，'p' 标点
+	Text string // 单词原文、去掉引号的标识符或字符串值、标点字符
+	Pos  int    // 在源文本中的起止位置
+	End  int
+}
+
+// sqlStatement 一条语句的词法单元
+type sqlStatement struct {
+	Tokens []sqlToken
+}
+
+var (
+	dumpServerVersion = regexp.MustCompile(`(?m)^-- Server version\s+(\S+)`)
+	dumpDatabase      = regexp.MustCompile(`(?m)^-- Host: .*Database: (\S+)`)
+)
+
+// parseSchemaFile 解析 mysqldump 导出的表结构文件
+// schema 依次取 USE 语句、mysqldump 文件头中的数据库名和文件名
+func parseSchemaFile(filePath string) (*SchemaSnapshot, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取 SQL 文件失败: %v", err)
+	}
+	src := string(data)
+	p := &ddlParser{src: src, file: filePath, tables: make(map[string]*TableSnapshot), views: make(map[string]bool)}
+	if m := dumpServerVersion.FindStringSubmatch(src); m != nil {
+		p.snapshot.ServerVersion = m[1]
+	}
+	if m := dumpDatabase.FindStringSubmatch(src); m != nil {
+		p.snapshot.Schema = m[1]
+	}
+	p.snapshot.Version = snapshotVersion
+
+	for _, stmt := range splitStatements(src) {
+		if err := p.statement(stmt); err != nil {
+			return nil, err
+		}
+	}
+	if p.snapshot.Schema == "" {
+		p.snapshot.Schema = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	}
+	return p.result(), nil
+}
+
+// splitStatements 按分隔符拆分语句，支持 DELIMITER 命令
+// 注释被忽略，/*!50003 ... */ 形式的版本注释中的内容按正常语句处理
+func splitStatements(src string) []sqlStatement {
+	var stmts []sqlStatement
+	var cur []sqlToken
+	flush := func() {
+		if len(cur) > 0 {
+			stmts = append(stmts, sqlStatement{Tokens: cur})
+			cur = nil
+		}
+	}
+
+	delimiter := ";"
+	versioned := false // 位于版本注释中
+	lineStart := true
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			lineStart = true
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+			continue
+		}
+
+		// DELIMITER 是客户端命令，只出现在行首，作用到行尾
+		if lineStart && len(src)-i > 10 && strings.EqualFold(src[i:i+9], "delimiter") && (src[i+9] == ' ' || src[i+9] == '\t') {
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			flush()
+			if d := strings.TrimSpace(src[i+9 : i+end]); d != "" {
+				delimiter = d
+			}
+			i += end
+			continue
+		}
+		lineStart = false
+
+		switch {
+		case strings.HasPrefix(src[i:], delimiter):
+			flush()
+			i += len(delimiter)
+		case c == '#' || strings.HasPrefix(src[i:], "--") && (i+2 == len(src) || strings.IndexByte(" \t\r\n", src[i+2]) >= 0):
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], "/*!"):
+			i += 3
+			for i < len(src) && src[i] >= '0' && src[i] <= '9' {
+				i++
+			}
+			versioned = true
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 4
+			}
+		case versioned && strings.HasPrefix(src[i:], "*/"):
+			versioned = false
+			i += 2
+		case c == '\'' || c == '"':
+			value, end := readQuoted(src, i)
+			cur = append(cur, sqlToken{Kind: 's', Text: value, Pos: i, End: end})
+			i = end
+		case c == '`':
+			value, end := readQuoted(src, i)
+			cur = append(cur, sqlToken{Kind: 'q', Text: value, Pos: i, End: end})
+			i = end
+		case (c == 'b' || c == 'B' || c == 'x' || c == 'X') && i+1 < len(src) && src[i+1] == '\'':
+			// 位串和十六进制字面量 b'01'、x'ff'，保留原文
+			_, end := readQuoted(src, i+1)
+			cur = append(cur, sqlToken{Kind: 'w', Text: strings.ToLower(src[i:i+1]) + src[i+1:end], Pos: i, End: end})
+			i = end
+		case isWordByte(c):
+			end := i
+			// DELIMITER $$ 时分隔符可以紧跟在单词之后，如 END$$
+			for end < len(src) && (isWordByte(src[end]) || c >= '0' && c <= '9' && src[end] == '.') && !strings.HasPrefix(src[end:], delimiter) {
+				end++
+			}
+			cur = append(cur, sqlToken{Kind: 'w', Text: src[i:end], Pos: i, End: end})
+			i = end
+		default:
+			_, size := utf8.DecodeRuneInString(src[i:])
+			cur = append(cur, sqlToken{Kind: 'p', Text: src[i : i+size], Pos: i, End: i + size})
+			i += size
+		}
+	}
+	flush()
+	return stmts
+}
+
+func isWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '$' || c >= utf8.RuneSelf
+}
+
+// readQuoted 读取从 start 开始的引号内容，返回解码后的值和结束位置
+// 连续两个引号表示引号本身；字符串中的反斜杠转义按 MySQL 的规则解码，反引号标识符中没有转义
+func readQuoted(src string, start int) (string, int) {
+	quote := src[start]
+	var b strings.Builder
+	i := start + 1
+	for i < len(src) {
+		c := src[i]
+		switch {
+		case c == quote && i+1 < len(src) && src[i+1] == quote:
+			b.WriteByte(quote)
+			i += 2
+		case c == quote:
+			return b.String(), i + 1
+		case c == '\\' && quote != '`' && i+1 < len(src):
+			switch next := src[i+1]; next {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '0':
+				b.WriteByte(0)
+			case 'b':
+				b.WriteByte('\b')
+			case 'Z':
+				b.WriteByte(26)
+			case '%', '_':
+				b.WriteByte('\\')
+				b.WriteByte(next)
+			default:
+				b.WriteByte(next)
+			}
+			i += 2
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String(), len(src)
+}
+
+// tokenStream 逐个读取词法单元
+type tokenStream struct {
+	toks []sqlToken
+	i    int
+}
+
+func (s *tokenStream) done() bool { return s.i >= len(s.toks) }
+
+func (s *tokenStream) peek() sqlToken {
+	if s.done() {
+		return sqlToken{}
+	}
+	return s.toks[s.i]
+}
+
+func (s *tokenStream) next() sqlToken {
+	tok := s.peek()
+	if !s.done() {
+		s.i++
+	}
+	return tok
+}
+
+// isWord 判断下一个词法单元是否为指定的关键字之一
+func (s *tokenStream) isWord(words ...string) bool {
+	tok := s.peek()
+	if tok.Kind != 'w' {
+		return false
+	}
+	for _, word := range words {
+		if strings.EqualFold(tok.Text, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptWords 依次匹配关键字，全部匹配时消耗它们
+func (s *tokenStream) acceptWords(words ...string) bool {
+	for k, word := range words {
+		if s.i+k >= len(s.toks) {
+			return false
+		}
+		tok := s.toks[s.i+k]
+		if tok.Kind != 'w' || !strings.EqualFold(tok.Text, word) {
+			return false
+		}
+	}
+	s.i += len(words)
+	return true
+}
+
+func (s *tokenStream) isPunct(p string) bool {
+	tok := s.peek()
+	return tok.Kind == 'p' && tok.Text == p
+}
+
+func (s *tokenStream) acceptPunct(p string) bool {
+	if s.isPunct(p) {
+		s.i++
+		return true
+	}
+	return false
+}
+
+// ident 读取标识符，不是标识符时返回 false
+func (s *tokenStream) ident() (string, bool) {
+	tok := s.peek()
+	if tok.Kind != 'w' && tok.Kind != 'q' && tok.Kind != 's' {
+		return "", false
+	}
+	s.i++
+	return tok.Text, true
+}
+
+// qualifiedName 读取可能带库名的名称，如 `db`.`t`
+func (s *tokenStream) qualifiedName() (schema, name string, ok bool) {
+	name, ok = s.ident()
+	if ok && s.acceptPunct(".") {
+		schema = name
+		name, ok = s.ident()
+	}
+	return schema, name, ok
+}
+
+// group 读取括号中的词法单元，当前位置必须是左括号
+func (s *tokenStream) group() []sqlToken {
+	start := s.i + 1
+	depth := 0
+	for !s.done() {
+		tok := s.next()
+		if tok.Kind != 'p' {
+			continue
+		}
+		switch tok.Text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				return s.toks[start : s.i-1]
+			}
+		}
+	}
+	return s.toks[start:]
+}
+
+// splitComma 按不在括号中的逗号拆分
+func splitComma(toks []sqlToken) [][]sqlToken {
+	var parts [][]sqlToken
+	depth, start := 0, 0
+	for i, tok := range toks {
+		if tok.Kind != 'p' {
+			continue
+		}
+		switch tok.Text {
+		case "(":
+			depth++
+		case ")":
+			depth--
+		case ",":
+			if depth == 0 {
+				parts = append(parts, toks[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if start < len(toks) {
+		parts = append(parts, toks[start:])
+	}
+	return parts
+}
+
+// tokenText 按 MySQL 显示列类型的格式拼接词法单元，如 10,2、'a','b'
+func tokenText(toks []sqlToken) string {
+	var b strings.Builder
+	for _, tok := range toks {
+		switch tok.Kind {
+		case 's':
+			b.WriteString("'" + strings.ReplaceAll(tok.Text, "'", "''") + "'")
+		case 'q':
+			b.WriteString("`" + strings.ReplaceAll(tok.Text, "`", "``") + "`")
+		default:
+			b.WriteString(tok.Text)
+		}
+	}
+	return b.String()
+}
+
+// sqlType 列或参数的数据类型
+type sqlType struct {
+	Name      string   // 小写的基础类型
+	Args      []string // 括号中的参数，字符串参数为解码后的值
+	ArgsText  string   // 括号中的参数原文
+	Unsigned  bool
+	Zerofill  bool
+	Charset   string
+	Collation string
+}
+
+// typeAliases MySQL 接受的类型别名，information_schema 中显示为右侧的类型
+var typeAliases = map[string]string{
+	"integer": "int",
+	"bool":    "tinyint",
+	"boolean": "tinyint",
+	"dec":     "decimal",
+	"numeric": "decimal",
+	"fixed":   "decimal",
+	"real":    "double",
+}
+
+// columnType 返回 information_schema 中的 COLUMN_TYPE，如 int(11) unsigned
+func (t sqlType) columnType() string {
+	s := t.Name
+	if t.ArgsText != "" {
+		s += "(" + t.ArgsText + ")"
+	}
+	if t.Unsigned {
+		s += " unsigned"
+	}
+	if t.Zerofill {
+		s += " zerofill"
+	}
+	return s
+}
+
+// isString 判断是否为有字符集的类型
+func (t sqlType) isString() bool {
+	switch t.Name {
+	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext", "enum", "set":
+		return true
+	}
+	return false
+}
+
+func (t sqlType) intArg(i int, def int64) int64 {
+	if i < len(t.Args) {
+		if n, err := strconv.ParseInt(t.Args[i], 10, 64); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
+// charMaxLength 返回 CHARACTER_MAXIMUM_LENGTH
+func (t sqlType) charMaxLength() *int64 {
+	var n int64
+	switch t.Name {
+	case "char", "binary":
+		n = t.intArg(0, 1)
+	case "varchar", "varbinary":
+		n = t.intArg(0, 0)
+	case "tinytext", "tinyblob":
+		n = 255
+	case "text", "blob":
+		n = 65535
+	case "mediumtext", "mediumblob":
+		n = 16777215
+	case "longtext", "longblob":
+		n = 4294967295
+	case "enum":
+		for _, value := range t.Args {
+			n = max(n, int64(utf8.RuneCountInString(value)))
+		}
+	case "set":
+		for i, value := range t.Args {
+			if i > 0 {
+				n++
+			}
+			n += int64(utf8.RuneCountInString(value))
+		}
+	default:
+		return nil
+	}
+	return &n
+}
+
+// integerPrecision 整数类型的 NUMERIC_PRECISION，分别为有符号和无符号
+var integerPrecision = map[string][2]int64{
+	"tinyint":   {3, 3},
+	"smallint":  {5, 5},
+	"mediumint": {7, 8},
+	"int":       {10, 10},
+	"bigint":    {19, 20},
+}
+
+// numericPrecision 返回 NUMERIC_PRECISION 和 NUMERIC_SCALE
+func (t sqlType) numericPrecision() (precision, scale *int64) {
+	ptr := func(n int64) *int64 { return &n }
+	if p, ok := integerPrecision[t.Name]; ok {
+		if t.Unsigned {
+			return ptr(p[1]), ptr(0)
+		}
+		return ptr(p[0]), ptr(0)
+	}
+	switch t.Name {
+	case "decimal":
+		return ptr(t.intArg(0, 10)), ptr(t.intArg(1, 0))
+	case "float", "double":
+		if len(t.Args) == 2 {
+			return ptr(t.intArg(0, 0)), ptr(t.intArg(1, 0))
+		}
+		if t.Name == "float" {
+			return ptr(12), nil
+		}
+		return ptr(22), nil
+	case "bit":
+		return ptr(t.intArg(0, 1)), nil
+	}
+	return nil, nil
+}
+
+// datetimePrecision 返回 DATETIME_PRECISION
+func (t sqlType) datetimePrecision() *int64 {
+	switch t.Name {
+	case "datetime", "timestamp", "time":
+		n := t.intArg(0, 0)
+		return &n
+	}
+	return nil
+}
+
+// parseType 读取数据类型及紧随其后的 UNSIGNED、ZEROFILL、字符集和排序规则
+func parseType(s *tokenStream) (sqlType, error) {
+	tok := s.next()
+	if tok.Kind != 'w' {
+		return sqlType{}, fmt.Errorf("缺少数据类型")
+	}
+	t := sqlType{Name: strings.ToLower(tok.Text)}
+	if t.Name == "double" {
+		s.acceptWords("precision")
+	}
+	if t.Name == "bool" || t.Name == "boolean" {
+		t.ArgsText, t.Args = "1", []string{"1"}
+	}
+	if alias, ok := typeAliases[t.Name]; ok {
+		t.Name = alias
+	}
+	if s.isPunct("(") {
+		args := s.group()
+		t.ArgsText = tokenText(args)
+		for _, arg := range splitComma(args) {
+			if len(arg) == 1 {
+				t.Args = append(t.Args, arg[0].Text)
+			}
+		}
+	}
+	for {
+		switch {
+		case s.acceptWords("unsigned"):
+			t.Unsigned = true
+		case s.acceptWords("signed"):
+		case s.acceptWords("zerofill"):
+			t.Zerofill, t.Unsigned = true, true
+		case acceptCharset(s, &t):
+		default:
+			return t, nil
+		}
+	}
+}
+
+// acceptCharset 读取 CHARACTER SET、CHARSET 或 COLLATE
+func acceptCharset(s *tokenStream, t *sqlType) bool {
+	switch {
+	case s.acceptWords("character", "set"), s.acceptWords("charset"):
+		s.acceptPunct("=")
+		name, _ := s.ident()
+		t.Charset = strings.ToLower(name)
+	case s.acceptWords("collate"):
+		s.acceptPunct("=")
+		name, _ := s.ident()
+		t.Collation = strings.ToLower(name)
+	default:
+		return false
+	}
+	return true
+}
+
+// ddlParser 解析 SQL 文件的状态
+type ddlParser struct {
+	src      string
+	file     string
+	snapshot SchemaSnapshot
+	tables   map[string]*TableSnapshot
+	views    map[string]bool
+	routines []ProcedureInfo
+}
+
+// errorf 返回带文件名和行号的错误
+func (p *ddlParser) errorf(tok sqlToken, format string, args ...interface{}) error {
+	line := strings.Count(p.src[:min(tok.Pos, len(p.src))], "\n") + 1
+	return fmt.Errorf("%s:%d: %s", p.file, line, fmt.Sprintf(format, args...))
+}
+
+// statement 处理一条语句，与表结构无关的语句被忽略
+func (p *ddlParser) statement(stmt sqlStatement) error {
+	s := &tokenStream{toks: stmt.Tokens}
+	switch {
+	case s.acceptWords("use"):
+		name, _ := s.ident()
+		return p.useSchema(stmt.Tokens[0], name)
+	case s.acceptWords("create"):
+	default:
+		return nil
+	}
+
+	// 跳过 OR REPLACE、DEFINER=...、ALGORITHM=... 等修饰，直到对象类型
+	for !s.done() {
+		switch {
+		case s.acceptWords("database"), s.acceptWords("schema"):
+			return p.createDatabase(s)
+		case s.acceptWords("table"):
+			return p.createTable(s)
+		case s.acceptWords("view"):
+			// mysqldump 先创建同名的临时视图或表，最后才创建实际的视图
+			if _, name, ok := s.qualifiedName(); ok {
+				p.views[name] = true
+				delete(p.tables, name)
+			}
+			return nil
+		case s.acceptWords("procedure"):
+			return p.createRoutine(s, "PROCEDURE")
+		case s.acceptWords("function"):
+			return p.createRoutine(s, "FUNCTION")
+		case s.isWord("trigger", "event", "index", "user", "role", "tablespace", "server", "spatial"):
+			return nil
+		}
+		s.next()
+	}
+	return nil
+}
+
+// useSchema 记录语句所属的 schema，一个文件只能包含一个数据库
+func (p *ddlParser) useSchema(tok sqlToken, name string) error {
+	if name == "" {
+		return nil
+	}
+	if p.snapshot.Schema != "" && p.snapshot.Schema != name && (len(p.tables) > 0 || len(p.routines) > 0) {
+		return p.errorf(tok, "文件包含多个数据库 %s 和 %s，请为每个数据库分别导出", p.snapshot.Schema, name)
+	}
+	p.snapshot.Schema = name
+	return nil
+}
+
+// createDatabase 解析 CREATE DATABASE，读取默认字符集和排序规则
+func (p *ddlParser) createDatabase(s *tokenStream) error {
+	s.acceptWords("if", "not", "exists")
+	first := s.peek()
+	name, ok := s.ident()
+	if !ok {
+		return nil
+	}
+	if err := p.useSchema(first, name); err != nil {
+		return err
+	}
+	var t sqlType
+	for !s.done() {
+		if !acceptCharset(s, &t) {
+			s.next()
+		}
+	}
+	p.snapshot.Charset, p.snapshot.Collation = t.Charset, t.Collation
+	return nil
+}
+
+// createTable 解析 CREATE TABLE
+func (p *ddlParser) createTable(s *tokenStream) error {
+	s.acceptWords("if", "not", "exists")
+	start := s.peek()
+	_, name, ok := s.qualifiedName()
+	if !ok {
+		return p.errorf(start, "CREATE TABLE 缺少表名")
+	}
+	if !s.isPunct("(") {
+		fmt.Printf("警告: 表 %s 不是以列定义创建的（LIKE 或 SELECT），已跳过\n", name)
+		return nil
+	}
+
+	table := &TableSnapshot{Name: name, Type: "BASE TABLE"}
+	var columnTypes []sqlType
+	for _, def := range splitComma(s.group()) {
+		d := &tokenStream{toks: def}
+		if d.done() {
+			continue
+		}
+		isIndex, err := p.indexDefinition(d, table)
+		if err != nil {
+			return err
+		}
+		if isIndex {
+			continue
+		}
+		column, t, err := p.columnDefinition(d, table)
+		if err != nil {
+			return err
+		}
+		table.Columns = append(table.Columns, column)
+		columnTypes = append(columnTypes, t)
+	}
+
+	// 表选项
+	var options sqlType
+	for !s.done() && !s.isWord("partition") {
+		switch {
+		case s.acceptWords("engine"):
+			s.acceptPunct("=")
+			table.Engine, _ = s.ident()
+		case s.acceptWords("comment"):
+			s.acceptPunct("=")
+			table.Comment = s.next().Text
+		case acceptCharset(s, &options):
+		default:
+			s.next()
+		}
+	}
+	tableCharset, tableCollation := p.charsetCollation(options, p.snapshot.Charset, p.snapshot.Collation)
+	table.Collation = tableCollation
+
+	for i := range table.Columns {
+		if columnTypes[i].isString() {
+			table.Columns[i].Charset, table.Columns[i].Collation = p.charsetCollation(columnTypes[i], tableCharset, tableCollation)
+		}
+	}
+	setColumnKeys(table)
+	// information_schema.STATISTICS 按索引名排序，不区分大小写
+	sort.SliceStable(table.Indexes, func(i, j int) bool {
+		return strings.ToLower(table.Indexes[i].Name) < strings.ToLower(table.Indexes[j].Name)
+	})
+	p.tables[name] = table
+	return nil
+}
+
+// charsetCollation 返回类型声明的字符集和排序规则，未声明时继承上一级
+func (p *ddlParser) charsetCollation(t sqlType, parentCharset, parentCollation string) (string, string) {
+	charset, collation := t.Charset, t.Collation
+	if charset == "" && collation != "" {
+		charset = strings.SplitN(collation, "_", 2)[0]
+	}
+	if charset == "" {
+		charset = parentCharset
+	}
+	if collation == "" {
+		if charset == parentCharset && parentCollation != "" {
+			collation = parentCollation
+		} else if charset != "" {
+			collation = defaultCollation(charset, p.snapshot.ServerVersion)
+		}
+	}
+	return charset, collation
+}
+
+// defaultCollation 返回字符集的默认排序规则
+func defaultCollation(charset, serverVersion string) string {
+	switch charset {
+	case "utf8mb4":
+		if isMySQL8(serverVersion) {
+			return "utf8mb4_0900_ai_ci"
+		}
+		return "utf8mb4_general_ci"
+	case "latin1":
+		return "latin1_swedish_ci"
+	case "gbk", "gb2312", "gb18030", "big5":
+		return charset + "_chinese_ci"
+	case "binary":
+		return "binary"
+	}
+	return charset + "_general_ci"
+}
+
+// isMySQL8 判断是否为 MySQL 8 及以上版本，版本未知时按 MySQL 8 处理
+func isMySQL8(serverVersion string) bool {
+	if strings.Contains(serverVersion, "MariaDB") {
+		return false
+	}
+	major, _, _ := strings.Cut(serverVersion, ".")
+	n, err := strconv.Atoi(major)
+	return err != nil || n >= 8
+}
+
+// indexDefinition 解析表定义中的索引和约束，不是索引时返回 false 且不消耗词法单元
+func (p *ddlParser) indexDefinition(d *tokenStream, table *TableSnapshot) (bool, error) {
+	var constraint string
+	start := d.i
+	if d.acceptWords("constraint") {
+		if !d.isWord("primary", "unique", "foreign", "check") {
+			constraint, _ = d.ident()
+		}
+	}
+
+	var index IndexSnapshot
+	switch {
+	case d.acceptWords("primary", "key"):
+		index = IndexSnapshot{Name: "PRIMARY", Unique: true}
+	case d.acceptWords("unique"):
+		index.Unique = true
+		if !d.acceptWords("key") {
+			d.acceptWords("index")
+		}
+	case d.acceptWords("key"), d.acceptWords("index"):
+	case d.acceptWords("fulltext"), d.acceptWords("spatial"):
+		if !d.acceptWords("key") {
+			d.acceptWords("index")
+		}
+	case d.acceptWords("foreign", "key"):
+		return true, p.foreignKey(d, table, constraint)
+	case d.acceptWords("check"):
+		return true, nil
+	default:
+		d.i = start
+		return false, nil
+	}
+
+	if index.Name == "" && !d.isPunct("(") && !d.isWord("using") {
+		index.Name, _ = d.ident()
+	}
+	if index.Name == "" {
+		index.Name = constraint
+	}
+	if d.acceptWords("using") {
+		d.next()
+	}
+	if !d.isPunct("(") {
+		return true, p.errorf(d.peek(), "表 %s 的索引定义缺少列", table.Name)
+	}
+	index.Columns = keyParts(d.group())
+	if index.Name == "" && len(index.Columns) > 0 {
+		// 没有名称的索引按 MySQL 的规则用第一列命名
+		index.Name = uniqueIndexName(table, index.Columns[0])
+	}
+	table.Indexes = append(table.Indexes, index)
+	return true, nil
+}
+
+// keyParts 返回索引中的列名，如 (`name`(10) DESC, `id`)；表达式索引的列名为空
+func keyParts(toks []sqlToken) []string {
+	var columns []string
+	for _, part := range splitComma(toks) {
+		name := ""
+		if len(part) > 0 && part[0].Kind != 'p' {
+			name = part[0].Text
+		}
+		columns = append(columns, name)
+	}
+	return columns
+}
+
+// uniqueIndexName 为没有名称的索引生成名称：第一列名，重名时加 _2、_3 后缀
+func uniqueIndexName(table *TableSnapshot, column string) string {
+	taken := func(name string) bool {
+		for _, index := range table.Indexes {
+			if strings.EqualFold(index.Name, name) {
+				return true
+			}
+		}
+		return false
+	}
+	name := column
+	for n := 2; taken(name); n++ {
+		name = fmt.Sprintf("%s_%d", column, n)
+	}
+	return name
+}
+
+// foreignKey 解析 FOREIGN KEY，只记录引用同一 schema 中的表的外键
+func (p *ddlParser) foreignKey(d *tokenStream, table *TableSnapshot, constraint string) error {
+	if !d.isPunct("(") {
+		name, _ := d.ident()
+		if constraint == "" {
+			constraint = name
+		}
+	}
+	if !d.isPunct("(") {
+		return p.errorf(d.peek(), "表 %s 的外键定义缺少列", table.Name)
+	}
+	columns := keyParts(d.group())
+	if !d.acceptWords("references") {
+		return p.errorf(d.peek(), "表 %s 的外键定义缺少 REFERENCES", table.Name)
+	}
+	refSchema, refTable, _ := d.qualifiedName()
+	if !d.isPunct("(") {
+		return p.errorf(d.peek(), "表 %s 的外键定义缺少引用的列", table.Name)
+	}
+	refColumns := keyParts(d.group())
+	if refSchema != "" && refSchema != p.snapshot.Schema {
+		return nil
+	}
+	if len(refColumns) != len(columns) {
+		return p.errorf(d.peek(), "表 %s 的外键列数与引用的列数不同", table.Name)
+	}
+	if constraint == "" {
+		constraint = fmt.Sprintf("%s_ibfk_%d", table.Name, len(table.ForeignKeys)+1)
+	}
+	table.ForeignKeys = append(table.ForeignKeys, ForeignKeySnapshot{Name: constraint, Columns: columns, RefTable: refTable, RefColumns: refColumns})
+	return nil
+}
+
+// columnDefinition 解析列定义，列上的 PRIMARY KEY、UNIQUE 转换为索引
+func (p *ddlParser) columnDefinition(d *tokenStream, table *TableSnapshot) (ColumnSnapshot, sqlType, error) {
+	start := d.peek()
+	name, ok := d.ident()
+	if !ok {
+		return ColumnSnapshot{}, sqlType{}, p.errorf(start, "表 %s 的列定义缺少列名", table.Name)
+	}
+	t, err := parseType(d)
+	if err != nil {
+		return ColumnSnapshot{}, sqlType{}, p.errorf(start, "表 %s 的列 %s %v", table.Name, name, err)
+	}
+
+	column := ColumnSnapshot{Name: name, Nullable: true}
+	var extra []string
+	defaultGenerated := false
+	for !d.done() {
+		switch {
+		case d.acceptWords("not", "null"):
+			column.Nullable = false
+		case d.acceptWords("null"):
+		case d.acceptWords("default"):
+			column.Default, defaultGenerated = parseDefault(d)
+		case d.acceptWords("auto_increment"):
+			extra = append(extra, "auto_increment")
+		case d.acceptWords("comment"):
+			column.Comment = d.next().Text
+		case d.acceptWords("primary", "key"), d.acceptWords("key"):
+			column.Nullable = false
+			table.Indexes = append(table.Indexes, IndexSnapshot{Name: "PRIMARY", Unique: true, Columns: []string{name}})
+		case d.acceptWords("unique"):
+			d.acceptWords("key")
+			table.Indexes = append(table.Indexes, IndexSnapshot{Name: uniqueIndexName(table, name), Unique: true, Columns: []string{name}})
+		case d.acceptWords("on", "update"):
+			expr := d.next().Text
+			if d.isPunct("(") {
+				expr += "(" + tokenText(d.group()) + ")"
+			}
+			extra = append(extra, "on update "+expr)
+		case d.acceptWords("generated", "always"), d.acceptWords("as"):
+			d.acceptWords("as")
+			if d.isPunct("(") {
+				d.group()
+			}
+		case d.acceptWords("virtual"), d.acceptWords("stored"), d.acceptWords("persistent"):
+			kind := strings.ToUpper(d.toks[d.i-1].Text)
+			if kind == "PERSISTENT" {
+				kind = "STORED"
+			}
+			extra = append(extra, kind+" GENERATED")
+		case d.acceptWords("references"):
+			// 列上的 REFERENCES 会被 MySQL 忽略
+			d.i = len(d.toks)
+		case acceptCharset(d, &t):
+		case d.isPunct("("):
+			d.group()
+		default:
+			d.next()
+		}
+	}
+	if defaultGenerated && isMySQL8(p.snapshot.ServerVersion) {
+		extra = append([]string{"DEFAULT_GENERATED"}, extra...)
+	}
+
+	column.DataType = t.Name
+	column.ColumnType = t.columnType()
+	column.Extra = strings.Join(extra, " ")
+	column.CharMaxLength = t.charMaxLength()
+	column.NumericPrecision, column.NumericScale = t.numericPrecision()
+	column.DatetimePrecision = t.datetimePrecision()
+	return column, t, nil
+}
+
+// parseDefault 读取 DEFAULT 的值，返回 COLUMN_DEFAULT 和是否为表达式默认值
+func parseDefault(d *tokenStream) (*string, bool) {
+	str := func(s string) *string { return &s }
+	tok := d.peek()
+	switch {
+	case tok.Kind == 's':
+		d.next()
+		return str(tok.Text), false
+	case tok.Kind == 'w' && strings.HasPrefix(tok.Text, "_") && d.i+1 < len(d.toks) && d.toks[d.i+1].Kind == 's':
+		// 字符集前缀，如 _utf8mb4'abc'
+		d.next()
+		return str(d.next().Text), false
+	case d.acceptWords("null"):
+		return nil, false
+	case d.acceptWords("true"):
+		return str("1"), false
+	case d.acceptWords("false"):
+		return str("0"), false
+	case tok.Kind == 'p' && (tok.Text == "-" || tok.Text == "+"):
+		d.next()
+		value := d.next().Text
+		if tok.Text == "-" {
+			value = "-" + value
+		}
+		return str(value), false
+	case tok.Kind == 'p' && tok.Text == "(":
+		return str(tokenExpr(d.group())), true
+	case tok.Kind == 'w' && tok.Text[0] >= '0' && tok.Text[0] <= '9',
+		tok.Kind == 'w' && (strings.HasPrefix(tok.Text, "b'") || strings.HasPrefix(tok.Text, "x'")):
+		d.next()
+		return str(tok.Text), false
+	case tok.Kind == 'w':
+		// CURRENT_TIMESTAMP、CURRENT_TIMESTAMP(3)、current_timestamp() 等
+		d.next()
+		value := tok.Text
+		if d.isPunct("(") {
+			value += "(" + tokenText(d.group()) + ")"
+		}
+		return str(value), true
+	}
+	return nil, false
+}
+
+// tokenExpr 拼接表达式，单词之间保留一个空格
+func tokenExpr(toks []sqlToken) string {
+	var b strings.Builder
+	for i, tok := range toks {
+		if i > 0 && tok.Kind != 'p' && toks[i-1].Kind != 'p' {
+			b.WriteByte(' ')
+		}
+		b.WriteString(tokenText([]sqlToken{tok}))
+	}
+	return b.String()
+}
+
+// setColumnKeys 按 MySQL 的规则设置 COLUMN_KEY
+// PRI：主键中的列，没有主键时为第一个列都非空的唯一索引中的列；UNI：单列唯一索引的列；MUL：其它索引的第一列
+func setColumnKeys(table *TableSnapshot) {
+	nullable := make(map[string]bool)
+	for _, column := range table.Columns {
+		nullable[strings.ToLower(column.Name)] = column.Nullable
+	}
+	keys := make(map[string]string)
+	primary := -1
+	for i, index := range table.Indexes {
+		if index.Name == "PRIMARY" {
+			primary = i
+			break
+		}
+	}
+	if primary < 0 {
+	search:
+		for i, index := range table.Indexes {
+			if !index.Unique {
+				continue
+			}
+			for _, column := range index.Columns {
+				if isNull, ok := nullable[strings.ToLower(column)]; !ok || isNull {
+					continue search
+				}
+			}
+			primary = i
+			break
+		}
+	}
+	if primary >= 0 {
+		for _, column := range table.Indexes[primary].Columns {
+			keys[strings.ToLower(column)] = "PRI"
+		}
+	}
+	for i, index := range table.Indexes {
+		if len(index.Columns) == 0 || i == primary {
+			continue
+		}
+		first := strings.ToLower(index.Columns[0])
+		switch {
+		case keys[first] == "PRI":
+		case index.Unique && len(index.Columns) == 1:
+			keys[first] = "UNI"
+		case keys[first] == "":
+			keys[first] = "MUL"
+		}
+	}
+	for i := range table.Columns {
+		table.Columns[i].Key = keys[strings.ToLower(table.Columns[i].Name)]
+	}
+}
+
+// createRoutine 解析 CREATE PROCEDURE 和 CREATE FUNCTION
+func (p *ddlParser) createRoutine(s *tokenStream, routineType string) error {
+	start := s.peek()
+	_, name, ok := s.qualifiedName()
+	if !ok || !s.isPunct("(") {
+		return p.errorf(start, "CREATE %s 缺少名称或参数列表", routineType)
+	}
+	proc := ProcedureInfo{Name: name, Type: routineType}
+
+	for i, def := range splitComma(s.group()) {
+		d := &tokenStream{toks: def}
+		param := ProcedureParam{Ordinal: i + 1, Mode: "IN"}
+		if routineType == "PROCEDURE" && d.isWord("in", "out", "inout") {
+			param.Mode = strings.ToUpper(d.next().Text)
+		}
+		param.Name, ok = d.ident()
+		if !ok {
+			return p.errorf(start, "%s 的第 %d 个参数缺少名称", name, i+1)
+		}
+		t, err := parseType(d)
+		if err != nil {
+			return p.errorf(start, "%s 的参数 %s %v", name, param.Name, err)
+		}
+		param.DataType = t.Name
+		param.ColumnType = t.columnType()
+		param.Unsigned = t.Unsigned
+		if precision, scale := t.numericPrecision(); precision != nil {
+			param.Precision = int(*precision)
+			if scale != nil {
+				param.Scale = int(*scale)
+			}
+		}
+		proc.Parameters = append(proc.Parameters, param)
+	}
+
+	if routineType == "FUNCTION" {
+		if !s.acceptWords("returns") {
+			return p.errorf(start, "存储函数 %s 缺少 RETURNS", name)
+		}
+		t, err := parseType(s)
+		if err != nil {
+			return p.errorf(start, "存储函数 %s 的返回值 %v", name, err)
+		}
+		proc.ReturnType = t.columnType()
+	}
+
+	// 跳过特性，其后为过程体
+	for !s.done() {
+		switch {
+		case s.acceptWords("comment"):
+			s.next()
+		case s.acceptWords("language", "sql"), s.acceptWords("not", "deterministic"), s.acceptWords("deterministic"),
+			s.acceptWords("contains", "sql"), s.acceptWords("no", "sql"),
+			s.acceptWords("reads", "sql", "data"), s.acceptWords("modifies", "sql", "data"):
+		case s.acceptWords("sql", "security"):
+			s.next()
+		default:
+			last := s.toks[len(s.toks)-1]
+			proc.Definition = p.src[s.peek().Pos:last.End]
+			s.i = len(s.toks)
+		}
+	}
+
+	p.routines = append(p.routines, proc)
+	return nil
+}
+
+// result 返回按 information_schema 查询顺序排列的快照
+func (p *ddlParser) result() *SchemaSnapshot {
+	snapshot := p.snapshot
+	if snapshot.Collation == "" && snapshot.Charset != "" {
+		snapshot.Collation = defaultCollation(snapshot.Charset, snapshot.ServerVersion)
+	}
+	for _, table := range p.tables {
+		snapshot.Tables = append(snapshot.Tables, *table)
+	}
+	sort.Slice(snapshot.Tables, func(i, j int) bool { return snapshot.Tables[i].Name < snapshot.Tables[j].Name })
+
+	// 与 getAllProcedures 相同：存储过程在前，按名称排序
+	snapshot.Routines = p.routines
+	sort.SliceStable(snapshot.Routines, func(i, j int) bool {
+		a, b := snapshot.Routines[i], snapshot.Routines[j]
+		if a.Type != b.Type {
+			return a.Type > b.Type
+		}
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	})
+
+	views := make([]string, 0, len(p.views))
+	for view := range p.views {
+		views = append(views, view)
+	}
+	sort.Strings(views)
+	for _, view := range views {
+		fmt.Printf("警告: 无法从 SQL 中得到视图 %s 的列类型，不生成模型\n", view)
+	}
+	return &snapshot
+}
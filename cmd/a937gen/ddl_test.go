@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "用解析结果更新 testdata 中的 .json 快照")
+
+// TestParseSchemaFile 解析 testdata 中 mysqldump 导出的 .sql，与同名的 .json 快照比较
+func TestParseSchemaFile(t *testing.T) {
+	files, err := filepath.Glob("testdata/*.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("testdata 中没有 .sql 文件")
+	}
+	for _, file := range files {
+		got, err := parseSchemaFile(file)
+		if err != nil {
+			t.Errorf("parseSchemaFile(%s) error: %v", file, err)
+			continue
+		}
+		golden := strings.TrimSuffix(file, ".sql") + ".json"
+		if *updateGolden {
+			if err := writeSnapshot(golden, got); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := loadSnapshot(golden)
+		if err != nil {
+			t.Errorf("%v", err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			data, _ := json.MarshalIndent(got, "", "  ")
+			t.Errorf("parseSchemaFile(%s) 与 %s 不同，got:\n%s", file, golden, data)
+		}
+	}
+}
+
+func TestParseSchemaFileErrors(t *testing.T) {
+	tests := []struct {
+		name, sql, want string
+	}{
+		{"multi.sql", "USE `a`;\nCREATE TABLE t (id int);\nUSE `b`;\n", "multi.sql:3: 文件包含多个数据库 a 和 b"},
+		{"noname.sql", "CREATE TABLE (id int);", "CREATE TABLE 缺少表名"},
+		{"index.sql", "CREATE TABLE t (\n  id int,\n  KEY idx\n);", "表 t 的索引定义缺少列"},
+		{"fk.sql", "CREATE TABLE t (id int, FOREIGN KEY (id) REFERENCES u);", "外键定义缺少引用的列"},
+		{"func.sql", "CREATE FUNCTION f(a int) BEGIN RETURN 1; END", "存储函数 f 缺少 RETURNS"},
+	}
+	dir := t.TempDir()
+	for _, tt := range tests {
+		file := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(file, []byte(tt.sql), 0644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := parseSchemaFile(file)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseSchemaFile(%s) error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want [][]string // 每条语句的词法单元，格式为 类型:文本
+	}{
+		{"分号", "SELECT 1; SELECT 2", [][]string{{"w:SELECT", "w:1"}, {"w:SELECT", "w:2"}}},
+		{"空语句", "SELECT 1;;\n;SELECT 2;", [][]string{{"w:SELECT", "w:1"}, {"w:SELECT", "w:2"}}},
+		{
+			"DELIMITER ;;",
+			"DELIMITER ;;\nCREATE PROCEDURE p() BEGIN SELECT 1; SELECT 2; END ;;\nDELIMITER ;\nSELECT 3;",
+			[][]string{
+				{"w:CREATE", "w:PROCEDURE", "w:p", "p:(", "p:)", "w:BEGIN", "w:SELECT", "w:1", "p:;", "w:SELECT", "w:2", "p:;", "w:END"},
+				{"w:SELECT", "w:3"},
+			},
+		},
+		{
+			"小写 delimiter",
+			"delimiter $$\nSELECT 1; SELECT 2$$\ndelimiter ;\n",
+			[][]string{{"w:SELECT", "w:1", "p:;", "w:SELECT", "w:2"}},
+		},
+		{
+			"DELIMITER 只在行首",
+			"SELECT 'x' AS delimiter ;",
+			[][]string{{"w:SELECT", "s:x", "w:AS", "w:delimiter"}},
+		},
+		{
+			"版本注释",
+			"/*!40101 SET NAMES utf8 */;\n/*!50003 CREATE*/ /*!50020 DEFINER=`root`@`%`*/ /*!50003 PROCEDURE `p`() SELECT 1 */;",
+			[][]string{
+				{"w:SET", "w:NAMES", "w:utf8"},
+				{"w:CREATE", "w:DEFINER", "p:=", "q:root", "p:@", "q:%", "w:PROCEDURE", "q:p", "p:(", "p:)", "w:SELECT", "w:1"},
+			},
+		},
+		{
+			"版本注释中的分隔符",
+			"DELIMITER ;;\n/*!50003 CREATE PROCEDURE p() BEGIN SELECT 1; END */;;\nDELIMITER ;",
+			[][]string{{"w:CREATE", "w:PROCEDURE", "w:p", "p:(", "p:)", "w:BEGIN", "w:SELECT", "w:1", "p:;", "w:END"}},
+		},
+		{
+			"注释",
+			"-- DROP TABLE t;\n# SELECT 0;\n/* SELECT 1; */ SELECT 2; -- x;\nSELECT 3--1;",
+			[][]string{{"w:SELECT", "w:2"}, {"w:SELECT", "w:3", "p:-", "p:-", "w:1"}},
+		},
+		{
+			"字符串转义",
+			`SELECT 'it''s;', 'a\';b', "x;""y", 'tab\there', '50\%';`,
+			[][]string{{"w:SELECT", "s:it's;", "p:,", "s:a';b", "p:,", `s:x;"y`, "p:,", "s:tab\there", "p:,", `s:50\%`}},
+		},
+		{
+			"反引号标识符",
+			"CREATE TABLE `a;b` (`c``d` int, `e\\` int);",
+			[][]string{{"w:CREATE", "w:TABLE", "q:a;b", "p:(", "q:c`d", "w:int", "p:,", "q:e\\", "w:int", "p:)"}},
+		},
+		{
+			"字面量",
+			"SELECT b'01', X'ff', 1.5, _utf8mb4'中文';",
+			[][]string{{"w:SELECT", "w:b'01'", "p:,", "w:x'ff'", "p:,", "w:1.5", "p:,", "w:_utf8mb4", "s:中文"}},
+		},
+	}
+	for _, tt := range tests {
+		var got [][]string
+		for _, stmt := range splitStatements(tt.src) {
+			var toks []string
+			for _, tok := range stmt.Tokens {
+				toks = append(toks, string(tok.Kind)+":"+tok.Text)
+			}
+			got = append(got, toks)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: splitStatements(%q) =\n%q\nwant\n%q", tt.name, tt.src, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffOp 一行的比较结果
+type diffOp struct {
+	Kind byte // ' ' 相同，'-' 只在旧文件中，'+' 只在新文件中
+	Line string
+}
+
+// maxDiffCells 最长公共子序列表格的最大单元数，超过时整段按替换处理
+const maxDiffCells = 4 << 20
+
+// diffLines 按最长公共子序列比较两组行
+func diffLines(a, b []string) []diffOp {
+	// 去掉相同的开头和结尾，生成代码的改动通常很集中
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []diffOp
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// diffMiddle 用动态规划比较去掉公共首尾后的部分
+func diffMiddle(a, b []string) []diffOp {
+	var ops []diffOp
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] 为 a[i:] 和 b[j:] 的最长公共子序列长度
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// unifiedDiff 返回 unified 格式的差异，context 为每处改动前后保留的相同行数，内容相同时返回空字符串
+func unifiedDiff(fromName, toName, from, to string, context int) string {
+	ops := diffLines(splitLines(from), splitLines(to))
+
+	var changes []int
+	for k, op := range ops {
+		if op.Kind != ' ' {
+			changes = append(changes, k)
+		}
+	}
+	if len(changes) == 0 {
+		return ""
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	for c := 0; c < len(changes); {
+		// 相距不超过 2*context 行的改动合并为一个 hunk
+		last := c
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*context+1 {
+			last++
+		}
+		start := max(changes[c]-context, 0)
+		end := min(changes[last]+context+1, len(ops))
+
+		fromLine, toLine := 1, 1
+		for _, op := range ops[:start] {
+			if op.Kind != '+' {
+				fromLine++
+			}
+			if op.Kind != '-' {
+				toLine++
+			}
+		}
+		fromCount, toCount := 0, 0
+		for _, op := range ops[start:end] {
+			if op.Kind != '+' {
+				fromCount++
+			}
+			if op.Kind != '-' {
+				toCount++
+			}
+		}
+		// 按 diff 的约定，空范围的起始行为其前一行
+		if fromCount == 0 {
+			fromLine--
+		}
+		if toCount == 0 {
+			toLine--
+		}
+
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", fromLine, fromCount, toLine, toCount)
+		for _, op := range ops[start:end] {
+			out.WriteByte(op.Kind)
+			out.WriteString(op.Line)
+			out.WriteByte('\n')
+		}
+		c = last + 1
+	}
+	return out.String()
+}
+
+// splitLines 按行拆分，忽略末尾的换行
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
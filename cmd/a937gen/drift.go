@@ -0,0 +1,318 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// driftReport scan drift 的检查结果
+type driftReport struct {
+	Databases []databaseDrift `json:"databases"`
+}
+
+// databaseDrift 一个数据库的模型与表结构的差异
+type databaseDrift struct {
+	Name   string       `json:"name"`
+	Schema string       `json:"schema,omitempty"`
+	Models string       `json:"models"`           // 模型包目录
+	Source string       `json:"source,omitempty"` // 使用快照时为快照文件
+	Tables []tableDrift `json:"tables"`
+	Error  string       `json:"error,omitempty"`
+
+	NoTypeTag bool `json:"no_type_tag,omitempty"` // 模型没有 type 标签，没有比较列类型
+}
+
+// tableDrift 一个模型与表的差异
+type tableDrift struct {
+	Table   string        `json:"table"`
+	Struct  string        `json:"struct"`
+	File    string        `json:"file"`
+	Dropped bool          `json:"dropped,omitempty"` // 数据库中已没有该表
+	Columns []columnDrift `json:"columns,omitempty"`
+}
+
+// 列差异的种类
+const (
+	driftAdded    = "added"    // 数据库中有、模型中没有的列
+	driftDropped  = "dropped"  // 模型中有、数据库中已删除的列
+	driftType     = "type"     // 列类型不同
+	driftNullable = "nullable" // 可空性不同
+)
+
+// columnDrift 一列的差异
+type columnDrift struct {
+	Column   string `json:"column"`
+	Field    string `json:"field,omitempty"`    // 模型中的字段名
+	Kind     string `json:"kind"`               // added、dropped、type、nullable
+	Model    string `json:"model,omitempty"`    // 模型中的类型或可空性
+	Database string `json:"database,omitempty"` // 数据库中的类型或可空性
+}
+
+// runDrift 比较 models/<db> 中的模型与数据库或快照中的表结构
+func runDrift(args []string) error {
+	fs := newFlagSet("scan drift", "scan drift [参数]")
+	var cf configFlags
+	cf.register(fs)
+	format := fs.String("format", "text", "输出格式: text、json")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "json" {
+		fmt.Fprintf(fs.Output(), "不支持的输出格式 %q，可用: text、json\n", *format)
+		return errUsage
+	}
+
+	config, err := loadConfig(cf.config)
+	if err != nil {
+		return fmt.Errorf("加载配置文件失败: %v", err)
+	}
+	databases, err := config.selectDatabases(cf.db)
+	if err != nil {
+		return err
+	}
+	if err := useSnapshots(databases, cf.snapshot); err != nil {
+		return err
+	}
+
+	report := checkDrift(databases, config.Databases)
+	if *format == "json" {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化结果失败: %v", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		printDrift(report)
+	}
+
+	failed := failures{}
+	changed := 0
+	for _, result := range report.Databases {
+		if result.Error != "" {
+			failed.add(result.Name, fmt.Errorf("%s", result.Error))
+		}
+		changed += len(result.Tables)
+	}
+	if err := failed.err("检查"); err != nil {
+		return err
+	}
+	if changed > 0 {
+		return fmt.Errorf("%w: %d 个模型与表结构不一致", errDrift, changed)
+	}
+	return nil
+}
+
+// checkDrift 检查每个数据库的模型，all 为配置中的全部数据库
+// 多个数据库共用模型目录时，只有这些数据库都参与检查才报告被删除的表，且表在任一数据库中存在即不算删除
+func checkDrift(databases, all []DatabaseConfig) driftReport {
+	report := driftReport{Databases: make([]databaseDrift, len(databases))}
+	missing := make([][]modelTable, len(databases)) // 每个数据库中找不到的模型
+	found := make(map[string]map[string]bool)       // 模型目录 -> 在某个数据库中找到的表
+	var checked []DatabaseConfig
+
+	for i, dbConfig := range databases {
+		result := &report.Databases[i]
+		result.Name, result.Models, result.Source = dbConfig.Name, dbConfig.modelPath(), dbConfig.Snapshot
+		result.Tables = []tableDrift{}
+		tables, err := driftDatabase(&dbConfig, result)
+		if err != nil {
+			result.Error = err.Error()
+			continue
+		}
+		checked = append(checked, dbConfig)
+		dir := absPath(dbConfig.modelPath())
+		if found[dir] == nil {
+			found[dir] = make(map[string]bool)
+		}
+		for name := range tables.found {
+			found[dir][name] = true
+		}
+		missing[i] = tables.missing
+	}
+
+	complete := completeDirs(all, checked)
+	reported := make(map[string]bool)
+	for i, dbConfig := range databases {
+		dir := absPath(dbConfig.modelPath())
+		if !complete[dir] {
+			continue
+		}
+		for _, table := range missing[i] {
+			if found[dir][table.Name] || reported[dir+"\x00"+table.Name] {
+				continue
+			}
+			reported[dir+"\x00"+table.Name] = true
+			report.Databases[i].Tables = append(report.Databases[i].Tables,
+				tableDrift{Table: table.Name, Struct: table.Struct, File: table.File, Dropped: true})
+		}
+	}
+	return report
+}
+
+// driftTables 一个数据库中找到和找不到的模型
+type driftTables struct {
+	found   map[string]bool
+	missing []modelTable
+}
+
+// driftDatabase 比较一个数据库中的表与模型的列，差异记录到 result
+func driftDatabase(dbConfig *DatabaseConfig, result *databaseDrift) (driftTables, error) {
+	tables := driftTables{found: make(map[string]bool)}
+	dir := dbConfig.modelPath()
+	models, err := readModelTables(dir)
+	if err != nil {
+		return tables, err
+	}
+	// 目录配置错误时所有表都查不到，不能当作没有差异
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return tables, fmt.Errorf("模型目录 %s 不存在，检查 out_path 或 model_pkg_path 配置", dir)
+	}
+	if len(models) == 0 {
+		return tables, fmt.Errorf("模型目录 %s 中没有带 TableName 方法的模型结构体，检查 out_path 或 model_pkg_path 配置", dir)
+	}
+
+	db, err := openDatabase(dbConfig)
+	if err != nil {
+		return tables, err
+	}
+	defer closeDB(db)
+	db = db.Session(&gorm.Session{Logger: db.Logger.LogMode(logger.Silent)})
+	result.Schema, _ = dbConfig.schemaName()
+
+	dbTables, err := db.Migrator().GetTables()
+	if err != nil {
+		return tables, fmt.Errorf("获取表列表失败: %v", err)
+	}
+	tableNames := make(map[string]string, len(dbTables))
+	for _, name := range dbTables {
+		tableNames[strings.ToLower(name)] = name
+	}
+
+	for _, name := range sortedTableNames(models) {
+		model := models[name]
+		dbTable, ok := tableNames[strings.ToLower(name)]
+		if !ok {
+			tables.missing = append(tables.missing, model)
+			continue
+		}
+		tables.found[name] = true
+
+		columnTypes, err := db.Migrator().ColumnTypes(dbTable)
+		if err != nil {
+			return tables, fmt.Errorf("读取表 %s 的列失败: %v", dbTable, err)
+		}
+		columns := driftColumns(model, columnTypes)
+		if len(columns) > 0 {
+			result.Tables = append(result.Tables, tableDrift{Table: name, Struct: model.Struct, File: model.File, Columns: columns})
+		}
+		for _, column := range model.Columns {
+			if column.DBType == "" {
+				result.NoTypeTag = true
+			}
+		}
+	}
+	return tables, nil
+}
+
+// driftColumns 比较模型的列与表的列，按表中列的顺序报告，其后为已删除的列
+func driftColumns(model modelTable, columnTypes []gorm.ColumnType) []columnDrift {
+	var drifts []columnDrift
+	inTable := make(map[string]bool, len(columnTypes))
+	for _, ct := range columnTypes {
+		name := ct.Name()
+		inTable[strings.ToLower(name)] = true
+		dbType, _ := ct.ColumnType()
+		nullable, _ := ct.Nullable()
+
+		column, ok := model.column(name)
+		if !ok {
+			drifts = append(drifts, columnDrift{Column: name, Kind: driftAdded, Database: dbType + " " + nullability(nullable)})
+			continue
+		}
+		if column.DBType != "" && normalizeColumnType(column.DBType) != normalizeColumnType(dbType) {
+			drifts = append(drifts, columnDrift{Column: name, Field: column.Field, Kind: driftType, Model: column.DBType, Database: dbType})
+		}
+		if column.NotNull == nullable {
+			drifts = append(drifts, columnDrift{Column: name, Field: column.Field, Kind: driftNullable,
+				Model: nullability(!column.NotNull), Database: nullability(nullable)})
+		}
+	}
+	for _, column := range model.Columns {
+		if !inTable[strings.ToLower(column.Name)] {
+			drifts = append(drifts, columnDrift{Column: column.Name, Field: column.Field, Kind: driftDropped, Model: column.signature()})
+		}
+	}
+	return drifts
+}
+
+func nullability(nullable bool) string {
+	if nullable {
+		return "null"
+	}
+	return "not null"
+}
+
+// integerDisplayWidth 整数类型的显示宽度，如 int(11)
+var integerDisplayWidth = regexp.MustCompile(`^((?:tiny|small|medium|big)?int)\(\d+\)`)
+
+// normalizeColumnType 规范化列类型用于比较：小写、合并空白，去掉整数类型的显示宽度
+// MySQL 8 不再显示整数的显示宽度（int(11) 显示为 int），tinyint(1) 除外
+func normalizeColumnType(columnType string) string {
+	s := strings.Join(strings.Fields(strings.ToLower(columnType)), " ")
+	if strings.HasPrefix(s, "tinyint(1)") {
+		return s
+	}
+	return integerDisplayWidth.ReplaceAllString(s, "$1")
+}
+
+// printDrift 输出每个数据库的差异
+func printDrift(report driftReport) {
+	changed := 0
+	for _, result := range report.Databases {
+		source := result.Schema
+		if result.Source != "" {
+			source = result.Source
+		}
+		switch {
+		case result.Error != "":
+			fmt.Printf("数据库 %s: 检查失败: %v\n", result.Name, result.Error)
+			continue
+		case len(result.Tables) == 0:
+			fmt.Printf("数据库 %s (%s): 模型 %s 与表结构一致\n", result.Name, source, result.Models)
+		default:
+			fmt.Printf("数据库 %s (%s): 模型 %s\n", result.Name, source, result.Models)
+		}
+		for _, table := range result.Tables {
+			changed++
+			if table.Dropped {
+				fmt.Printf("  - 表 %s: 数据库中已不存在 (%s, %s)\n", table.Table, table.Struct, table.File)
+				continue
+			}
+			fmt.Printf("  ~ 表 %s (%s, %s)\n", table.Table, table.Struct, table.File)
+			for _, column := range table.Columns {
+				switch column.Kind {
+				case driftAdded:
+					fmt.Printf("      + 列 %s %s: 模型中没有\n", column.Column, column.Database)
+				case driftDropped:
+					fmt.Printf("      - 列 %s (%s): 数据库中已删除\n", column.Column, column.Field)
+				case driftType:
+					fmt.Printf("      ~ 列 %s 类型: %s -> %s\n", column.Column, column.Model, column.Database)
+				case driftNullable:
+					fmt.Printf("      ~ 列 %s 可空: %s -> %s\n", column.Column, column.Model, column.Database)
+				}
+			}
+		}
+		if result.NoTypeTag {
+			fmt.Printf("  注意: 模型没有 type 标签（field_with_type_tag），没有比较列类型\n")
+		}
+	}
+	if changed > 0 {
+		fmt.Printf("\n%d 个模型与表结构不一致，请重新生成模型\n", changed)
+	}
+}
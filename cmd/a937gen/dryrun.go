@@ -0,0 +1,367 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// errDrift 表示 -dry-run 生成的结果与现有文件不同
+var errDrift = errors.New("drift")
+
+// dryRunModels 把模型生成到临时目录，与现有文件比较后删除临时目录，不修改现有文件
+// all 为配置中的全部数据库，用于判断模型目录是否被未生成的数据库共用
+func dryRunModels(databases, all []DatabaseConfig, globalConfig GlobalConfig, jobs int) error {
+	root, err := os.MkdirTemp("", "a937gen-dry-run-")
+	if err != nil {
+		return fmt.Errorf("创建临时目录失败: %v", err)
+	}
+	defer os.RemoveAll(root)
+
+	mirrored, err := mirrorDatabases(databases, root)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("试运行: 生成到临时目录 %s\n", root)
+
+	failed := failures{}
+	generateModels(mirrored, globalConfig, jobs, failed)
+
+	// 按目录比较，多个数据库共用的目录只比较一次
+	var generated []DatabaseConfig
+	var dirs []dirPair
+	seen := make(map[string]bool)
+	for i, dbConfig := range databases {
+		if _, ok := failed[dbConfig.Name]; ok {
+			continue
+		}
+		generated = append(generated, dbConfig)
+		for _, pair := range []dirPair{
+			{Real: dbConfig.OutPath, Temp: mirrored[i].OutPath},
+			{Real: dbConfig.modelPath(), Temp: mirrored[i].modelPath(), Model: true},
+		} {
+			if key := absPath(pair.Real); !seen[key] {
+				seen[key] = true
+				dirs = append(dirs, pair)
+			}
+		}
+	}
+	complete := completeDirs(all, generated)
+
+	var diffs []fileDiff
+	var changes []dirChanges
+	for _, pair := range dirs {
+		fileDiffs, err := compareDir(pair, complete[absPath(pair.Real)])
+		if err != nil {
+			return err
+		}
+		diffs = append(diffs, fileDiffs...)
+		if pair.Model {
+			tableChanges, err := compareModelTables(pair, complete[absPath(pair.Real)])
+			if err != nil {
+				return err
+			}
+			if len(tableChanges) > 0 {
+				changes = append(changes, dirChanges{Dir: pair.Real, Tables: tableChanges})
+			}
+		}
+	}
+
+	printDryRun(diffs, changes)
+	if err := failed.err("生成模型"); err != nil {
+		return err
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("%w: %d 个文件与生成结果不一致", errDrift, len(diffs))
+	}
+	fmt.Println("\n现有文件与生成结果一致")
+	return nil
+}
+
+// dirPair 实际目录和临时目录中对应的目录
+type dirPair struct {
+	Real  string
+	Temp  string
+	Model bool // 模型包目录
+}
+
+// mirrorDatabases 把数据库的输出目录映射到临时目录中相同的相对位置
+// 临时目录中复制 go.mod 和 go.sum，gorm/gen 解析出的模型包导入路径与实际目录相同，生成结果可以逐字节比较
+func mirrorDatabases(databases []DatabaseConfig, root string) ([]DatabaseConfig, error) {
+	moduleRoot, err := findModuleRoot()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"go.mod", "go.sum"} {
+		data, err := os.ReadFile(filepath.Join(moduleRoot, name))
+		if os.IsNotExist(err) && name == "go.sum" {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取 %s 失败: %v", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(root, name), data, 0644); err != nil {
+			return nil, fmt.Errorf("写入 %s 失败: %v", name, err)
+		}
+	}
+
+	mirror := func(path string) (string, error) {
+		rel, err := filepath.Rel(moduleRoot, absPath(path))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return "", fmt.Errorf("目录 %s 不在模块 %s 中，不能试运行", path, moduleRoot)
+		}
+		return filepath.Join(root, rel), nil
+	}
+
+	mirrored := make([]DatabaseConfig, len(databases))
+	for i, dbConfig := range databases {
+		outPath, err := mirror(dbConfig.OutPath)
+		if err != nil {
+			return nil, err
+		}
+		modelPath, err := mirror(dbConfig.modelPath())
+		if err != nil {
+			return nil, err
+		}
+		dbConfig.OutPath, dbConfig.ModelPkgPath = outPath, modelPath
+		mirrored[i] = dbConfig
+	}
+	return mirrored, nil
+}
+
+// findModuleRoot 从当前目录向上查找 go.mod 所在的目录
+func findModuleRoot() (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("获取当前目录失败: %v", err)
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("当前目录不在 Go 模块中，找不到 go.mod")
+		}
+		dir = parent
+	}
+}
+
+// absPath 返回绝对路径，失败时原样返回
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// completeDirs 返回本次生成覆盖了全部所属数据库的目录（绝对路径）
+// 只有这些目录中多出的现有文件和表才能确定是被删除的，否则可能属于未生成的数据库
+func completeDirs(all, generated []DatabaseConfig) map[string]bool {
+	done := make(map[string]bool)
+	for _, dbConfig := range generated {
+		done[dbConfig.Name] = true
+	}
+	complete := make(map[string]bool)
+	for _, dbConfig := range generated {
+		complete[absPath(dbConfig.OutPath)] = true
+		complete[absPath(dbConfig.modelPath())] = true
+	}
+	for _, dbConfig := range all {
+		if !done[dbConfig.Name] {
+			delete(complete, absPath(dbConfig.OutPath))
+			delete(complete, absPath(dbConfig.modelPath()))
+		}
+	}
+	return complete
+}
+
+// fileDiff 一个文件的差异
+type fileDiff struct {
+	Path   string
+	Status string // 新增、删除、修改
+	Diff   string
+}
+
+// isModelsFile 判断文件是否由 models 命令生成，存储过程包装方法和手写的文件不参与比较
+func isModelsFile(name string) bool {
+	if name == proceduresFile || name == proceduresFakeFile {
+		return false
+	}
+	return name == "gen.go" || strings.HasSuffix(name, ".gen.go")
+}
+
+// listModelsFiles 返回目录中由 models 命令生成的文件名，目录不存在时返回空
+func listModelsFiles(dir string) (map[string]bool, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取目录失败: %v", err)
+	}
+	files := make(map[string]bool)
+	for _, entry := range entries {
+		if !entry.IsDir() && isModelsFile(entry.Name()) {
+			files[entry.Name()] = true
+		}
+	}
+	return files, nil
+}
+
+// compareDir 比较目录中生成的文件，complete 为 false 时不报告临时目录中没有的现有文件
+func compareDir(pair dirPair, complete bool) ([]fileDiff, error) {
+	realFiles, err := listModelsFiles(pair.Real)
+	if err != nil {
+		return nil, err
+	}
+	tempFiles, err := listModelsFiles(pair.Temp)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for name := range tempFiles {
+		names[name] = true
+	}
+	if complete {
+		for name := range realFiles {
+			names[name] = true
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var diffs []fileDiff
+	for _, name := range sorted {
+		path := filepath.Join(pair.Real, name)
+		var current, generated string
+		if realFiles[name] {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("读取文件失败: %v", err)
+			}
+			current = string(data)
+		}
+		if tempFiles[name] {
+			data, err := os.ReadFile(filepath.Join(pair.Temp, name))
+			if err != nil {
+				return nil, fmt.Errorf("读取生成的文件失败: %v", err)
+			}
+			generated = string(data)
+		}
+
+		fromName, toName := "a/"+filepath.ToSlash(filepath.Clean(path)), "b/"+filepath.ToSlash(filepath.Clean(path))
+		status := "修改"
+		switch {
+		case !realFiles[name]:
+			fromName, status = "/dev/null", "新增"
+		case !tempFiles[name]:
+			toName, status = "/dev/null", "删除"
+		}
+		if diff := unifiedDiff(fromName, toName, current, generated, 3); diff != "" {
+			diffs = append(diffs, fileDiff{Path: path, Status: status, Diff: diff})
+		}
+	}
+	return diffs, nil
+}
+
+// tableChange 一个表的结构变化
+type tableChange struct {
+	Table   string
+	Kind    byte     // '+' 新增，'-' 删除，'~' 修改
+	Columns []string // 修改时的列变化，如 "+ 列 foo int32 int(11) not null"
+}
+
+// dirChanges 一个模型目录中的结构变化
+type dirChanges struct {
+	Dir    string
+	Tables []tableChange
+}
+
+// compareModelTables 比较现有模型和生成的模型中的表和列
+func compareModelTables(pair dirPair, complete bool) ([]tableChange, error) {
+	current, err := readModelTables(pair.Real)
+	if err != nil {
+		return nil, err
+	}
+	generated, err := readModelTables(pair.Temp)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []tableChange
+	for _, name := range sortedTableNames(generated) {
+		table, ok := current[name]
+		if !ok {
+			changes = append(changes, tableChange{Table: name, Kind: '+'})
+			continue
+		}
+		if columns := compareColumns(table, generated[name]); len(columns) > 0 {
+			changes = append(changes, tableChange{Table: name, Kind: '~', Columns: columns})
+		}
+	}
+	if complete {
+		for _, name := range sortedTableNames(current) {
+			if _, ok := generated[name]; !ok {
+				changes = append(changes, tableChange{Table: name, Kind: '-'})
+			}
+		}
+	}
+	return changes, nil
+}
+
+// compareColumns 返回列的新增、删除和类型变化
+func compareColumns(current, generated modelTable) []string {
+	var columns []string
+	for _, c := range generated.Columns {
+		prev, ok := current.column(c.Name)
+		switch {
+		case !ok:
+			columns = append(columns, fmt.Sprintf("+ 列 %s %s", c.Name, c.signature()))
+		case prev.signature() != c.signature():
+			columns = append(columns, fmt.Sprintf("~ 列 %s: %s -> %s", c.Name, prev.signature(), c.signature()))
+		}
+	}
+	for _, c := range current.Columns {
+		if _, ok := generated.column(c.Name); !ok {
+			columns = append(columns, fmt.Sprintf("- 列 %s %s", c.Name, c.signature()))
+		}
+	}
+	return columns
+}
+
+// printDryRun 输出每个文件的差异和结构变化汇总
+func printDryRun(diffs []fileDiff, changes []dirChanges) {
+	for _, diff := range diffs {
+		fmt.Printf("\n%s", diff.Diff)
+	}
+
+	if len(changes) > 0 {
+		fmt.Println("\n结构变化:")
+		for _, dir := range changes {
+			fmt.Printf("%s/\n", dir.Dir)
+			for _, table := range dir.Tables {
+				fmt.Printf("  %c 表 %s\n", table.Kind, table.Table)
+				for _, column := range table.Columns {
+					fmt.Printf("      %s\n", column)
+				}
+			}
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, diff := range diffs {
+		counts[diff.Status]++
+	}
+	fmt.Printf("\n文件差异: 新增 %d 个，删除 %d 个，修改 %d 个\n", counts["新增"], counts["删除"], counts["修改"])
+	for _, diff := range diffs {
+		fmt.Printf("  %s %s\n", diff.Status, diff.Path)
+	}
+}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"gorm.io/gen"
+	"gorm.io/gorm/schema"
+)
+
+// EnumConfig 枚举列配置，覆盖从列注释中解析出的枚举
+type EnumConfig struct {
+	Table  string      `yaml:"table"`
+	Column string      `yaml:"column"`
+	Type   string      `yaml:"type"`   // Go 类型名，默认 <模型名><字段名>
+	Values []EnumValue `yaml:"values"` // 枚举取值，为空时从列注释解析
+	Skip   bool        `yaml:"skip"`   // 不为该列生成枚举类型
+}
+
+// EnumValue 枚举的一个取值
+type EnumValue struct {
+	Value int64  `yaml:"value"`
+	Name  string `yaml:"name"`  // 常量名后缀，默认取 label
+	Label string `yaml:"label"` // String() 的返回值
+}
+
+// enumType 为一列生成的枚举类型
+type enumType struct {
+	Column  string
+	Field   string // 模型字段名
+	Name    string // Go 类型名
+	Base    string // 底层类型，如 int32、uint8
+	GenType string // 查询字段原本的类型，如 Int32
+	Comment string
+	Values  []EnumValue
+}
+
+// enumFile 一个表生成的枚举类型
+type enumFile struct {
+	FileName    string // gorm/gen 生成的文件名，不含 .gen.go
+	QueryStruct string
+	Enums       []enumType
+}
+
+// enumItemPattern 匹配 "0未处理"、"1:已处理"、"2=冻结" 形式的注释项
+var enumItemPattern = regexp.MustCompile(`^(-?\d+)\s*[:：=.\-]?\s*(\S.*)$`)
+
+// parseEnumComment 解析 "0未处理,1已处理" 形式的列注释，至少两项且取值不重复时才视为枚举
+// 数字和标签之间没有分隔符时按相邻取值切分，如 "4兑换,528game,6领取" 中的 528game 解析为 5 -> 28game
+func parseEnumComment(comment string) ([]EnumValue, bool) {
+	comment = strings.TrimSpace(comment)
+	// 允许 "状态:0未处理,1已处理"、"状态 0:等待 1:进行中"、"状态(0:新 1:成功)" 形式的前缀说明，
+	// 说明中没有数字和分隔符，枚举从第一个数字开始
+	runes := []rune(comment)
+	for i, r := range runes {
+		if r == '-' && i+1 < len(runes) {
+			r = runes[i+1]
+		}
+		if unicode.IsDigit(r) {
+			rest := string(runes[i:])
+			last, _ := utf8.DecodeLastRuneInString(strings.TrimSpace(string(runes[:i])))
+			if closing, ok := enumBrackets[last]; ok {
+				rest = strings.TrimSuffix(strings.TrimSpace(rest), string(closing))
+			}
+			return parseEnumItems(rest)
+		}
+		if isEnumSeparator(r) {
+			break
+		}
+	}
+	return nil, false
+}
+
+// enumBrackets 包住枚举说明的括号
+var enumBrackets = map[rune]rune{'(': ')', '（': '）', '[': ']', '【': '】'}
+
+// parseEnumItems 解析去掉前缀说明后的枚举项
+func parseEnumItems(comment string) ([]EnumValue, bool) {
+	items := splitEnumItems(comment)
+	if len(items) < 2 {
+		return nil, false
+	}
+
+	var values []EnumValue
+	var digits []string
+	for _, item := range items {
+		match := enumItemPattern.FindStringSubmatch(strings.TrimSpace(item))
+		if match == nil {
+			return nil, false
+		}
+		value, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, false
+		}
+		values = append(values, EnumValue{Value: value, Label: strings.TrimSpace(match[2])})
+		digits = append(digits, match[1])
+	}
+
+	// 前后两项连续而当前项打断了连续性时，把当前项多出的数字归入标签
+	for i := 1; i+1 < len(values); i++ {
+		expected := values[i-1].Value + 1
+		prefix := strconv.FormatInt(expected, 10)
+		if values[i].Value != expected && values[i+1].Value == expected+1 &&
+			len(digits[i]) > len(prefix) && strings.HasPrefix(digits[i], prefix) {
+			values[i] = EnumValue{Value: expected, Label: digits[i][len(prefix):] + values[i].Label}
+		}
+	}
+
+	seen := make(map[int64]bool)
+	for _, v := range values {
+		if v.Label == "" || seen[v.Value] {
+			return nil, false
+		}
+		seen[v.Value] = true
+	}
+	return values, true
+}
+
+// splitEnumItems 按逗号、分号、顿号切分注释项，"0待付款  1已付款" 这样以空白隔开的数字也视为新的一项
+func splitEnumItems(comment string) []string {
+	var items []string
+	var item strings.Builder
+	runes := []rune(comment)
+	for i, r := range runes {
+		switch {
+		case isEnumSeparator(r):
+			items = append(items, item.String())
+			item.Reset()
+		case unicode.IsSpace(r) && i+1 < len(runes) && unicode.IsDigit(runes[i+1]) && strings.TrimSpace(item.String()) != "" && !isNumber(item.String()):
+			items = append(items, item.String())
+			item.Reset()
+		default:
+			item.WriteRune(r)
+		}
+	}
+	items = append(items, item.String())
+
+	var result []string
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// isEnumSeparator 判断是否为注释项之间的分隔符
+func isEnumSeparator(r rune) bool {
+	return r == ',' || r == '，' || r == ';' || r == '；' || r == '、'
+}
+
+// isNumber 判断去掉空白后是否只有数字，"0 1" 这样的项不在空白处切分
+func isNumber(s string) bool {
+	_, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	return err == nil
+}
+
+// isIntegerType 判断模型字段类型是否为整数
+func isIntegerType(goType string) bool {
+	switch strings.TrimLeft(goType, "*") {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return true
+	}
+	return false
+}
+
+// fieldNaming gorm/gen 生成字段名使用的命名规则
+var fieldNaming = schema.NamingStrategy{SingularTable: true}
+
+// enumOpt 返回把整数枚举列替换为枚举类型的模型选项，生成的枚举追加到 enums
+// 必须放在修复注释的选项之后，以便解析修复后的注释
+func enumOpt(dbConfig DatabaseConfig, table, structName string, enums *[]enumType) gen.ModelOpt {
+	overrides := make(map[string]EnumConfig)
+	for _, enumConfig := range dbConfig.Enums {
+		if enumConfig.Table == table {
+			overrides[enumConfig.Column] = enumConfig
+		}
+	}
+
+	return gen.FieldModify(func(f gen.Field) gen.Field {
+		if !isIntegerType(f.Type) || f.CustomGenType != "" {
+			return f
+		}
+		override, hasOverride := overrides[f.ColumnName]
+		if override.Skip {
+			return f
+		}
+
+		values := override.Values
+		if len(values) == 0 {
+			var ok bool
+			if values, ok = parseEnumComment(f.ColumnComment); !ok {
+				if hasOverride {
+					fmt.Printf("警告: %s.%s 的注释无法解析为枚举，请在 enums 中配置 values\n", table, f.ColumnName)
+				}
+				return f
+			}
+		}
+
+		// gorm/gen 在执行选项之后才把列名转换为字段名，这里按同样的规则转换
+		fieldName := fieldNaming.SchemaName(f.ColumnName)
+		typeName := override.Type
+		if typeName == "" {
+			typeName = structName + fieldName
+		}
+		base := strings.TrimLeft(f.Type, "*")
+		*enums = append(*enums, enumType{
+			Column:  f.ColumnName,
+			Field:   fieldName,
+			Name:    typeName,
+			Base:    base,
+			GenType: f.GenType(),
+			Comment: f.ColumnComment,
+			Values:  values,
+		})
+
+		f.CustomGenType = f.GenType()
+		f.Type = strings.Repeat("*", len(f.Type)-len(base)) + typeName
+		return f
+	})
+}
+
+// constName 返回枚举取值的常量名
+func (e enumType) constName(v EnumValue) string {
+	name := v.Name
+	if name == "" {
+		name = v.Label
+	}
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+		return -1
+	}, name)
+	if name == "" {
+		name = strings.Replace(strconv.FormatInt(v.Value, 10), "-", "Neg", 1)
+	}
+	first, size := utf8.DecodeRuneInString(name)
+	return e.Name + string(unicode.ToUpper(first)) + name[size:]
+}
+
+// constNames 返回所有取值的常量名，重名时改用数字后缀
+func (e enumType) constNames() []string {
+	names := make([]string, len(e.Values))
+	count := make(map[string]int)
+	for i, v := range e.Values {
+		names[i] = e.constName(v)
+		count[names[i]]++
+	}
+	for i, v := range e.Values {
+		if count[names[i]] > 1 {
+			names[i] = e.constName(EnumValue{Value: v.Value})
+		}
+	}
+	return names
+}
+
+func (e enumType) unsigned() bool {
+	return strings.HasPrefix(e.Base, "uint")
+}
+
+// bitSize 返回底层类型的位数，用于 strconv 解析
+func (e enumType) bitSize() int {
+	size := strings.TrimPrefix(strings.TrimPrefix(e.Base, "u"), "int")
+	if size == "" {
+		return 64
+	}
+	n, _ := strconv.Atoi(size)
+	return n
+}
+
+// generateEnumFiles 生成表的枚举类型（模型包）和使用枚举类型的查询字段（查询包）
+func generateEnumFiles(dbConfig DatabaseConfig, fileName, queryStruct string, enums []enumType) error {
+	if len(enums) == 0 {
+		return nil
+	}
+	modelPkg := filepath.Base(filepath.Clean(dbConfig.modelPath()))
+
+	if err := writeGoFile(filepath.Join(dbConfig.modelPath(), fileName+"_enums.gen.go"), enumModelCode(modelPkg, enums)); err != nil {
+		return err
+	}
+
+	// 查询字段直接追加到 gorm/gen 生成的查询文件中，沿用其对模型包的导入
+	queryFile := filepath.Join(dbConfig.OutPath, fileName+".gen.go")
+	data, err := os.ReadFile(queryFile)
+	if err != nil {
+		return fmt.Errorf("读取查询文件失败: %v", err)
+	}
+	code := string(data)
+	for _, e := range enums {
+		fieldType := queryStruct + e.Field + "Field"
+		code = replaceQueryField(code, e.Field, e.Column, e.GenType, fieldType)
+		code += enumFieldCode(modelPkg, fieldType, e)
+	}
+	return writeGoFile(queryFile, code)
+}
+
+// replaceQueryField 把查询结构体中 gorm/gen 生成的 field.<genType> 字段替换为 fieldType，
+// 构造调用 field.New<genType>(table, "column") 替换为 new<FieldType>(table, "column")
+func replaceQueryField(code, fieldName, column, genType, fieldType string) string {
+	declPattern := regexp.MustCompile(`(?m)^(\t` + regexp.QuoteMeta(fieldName) + `\s+)field\.` + genType + `\b`)
+	newPattern := regexp.MustCompile(`field\.New` + genType + `\((\w+), "` + regexp.QuoteMeta(column) + `"\)`)
+	code = declPattern.ReplaceAllString(code, "${1}"+fieldType)
+	return newPattern.ReplaceAllString(code, "new"+strings.Title(fieldType)+`($1, "`+column+`")`)
+}
+
+// enumModelCode 生成模型包中的枚举类型
+func enumModelCode(pkg string, enums []enumType) string {
+	var code strings.Builder
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n")
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n")
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n\n")
+	code.WriteString(fmt.Sprintf("package %s\n\n", pkg))
+	code.WriteString("import (\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"strconv\"\n)\n")
+
+	for _, e := range enums {
+		names := e.constNames()
+		format, parse := "strconv.FormatInt(int64(e), 10)", "strconv.ParseInt"
+		if e.unsigned() {
+			format, parse = "strconv.FormatUint(uint64(e), 10)", "strconv.ParseUint"
+		}
+
+		code.WriteString(fmt.Sprintf("\n// %s %s\n", e.Name, strings.ReplaceAll(e.Comment, "\n", " ")))
+		code.WriteString(fmt.Sprintf("type %s %s\n\n", e.Name, e.Base))
+
+		code.WriteString("const (\n")
+		for i, v := range e.Values {
+			code.WriteString(fmt.Sprintf("\t%s %s = %d // %s\n", names[i], e.Name, v.Value, v.Label))
+		}
+		code.WriteString(")\n\n")
+
+		code.WriteString(fmt.Sprintf("// %sValues %s 的所有取值\n", e.Name, e.Name))
+		code.WriteString(fmt.Sprintf("var %sValues = []%s{%s}\n\n", e.Name, e.Name, strings.Join(names, ", ")))
+
+		code.WriteString("// String 返回取值对应的标签，未知取值返回数字\n")
+		code.WriteString(fmt.Sprintf("func (e %s) String() string {\n", e.Name))
+		code.WriteString("\tswitch e {\n")
+		for i, v := range e.Values {
+			code.WriteString(fmt.Sprintf("\tcase %s:\n\t\treturn %q\n", names[i], v.Label))
+		}
+		code.WriteString("\t}\n")
+		code.WriteString(fmt.Sprintf("\treturn %s\n}\n\n", format))
+
+		code.WriteString("// MarshalText 序列化为标签\n")
+		code.WriteString(fmt.Sprintf("func (e %s) MarshalText() ([]byte, error) {\n", e.Name))
+		code.WriteString("\treturn []byte(e.String()), nil\n}\n\n")
+
+		code.WriteString("// UnmarshalText 解析标签或数字\n")
+		code.WriteString(fmt.Sprintf("func (e *%s) UnmarshalText(text []byte) error {\n", e.Name))
+		code.WriteString(fmt.Sprintf("\tfor _, v := range %sValues {\n", e.Name))
+		code.WriteString("\t\tif v.String() == string(text) {\n\t\t\t*e = v\n\t\t\treturn nil\n\t\t}\n\t}\n")
+		code.WriteString(fmt.Sprintf("\tn, err := %s(string(text), 10, %d)\n", parse, e.bitSize()))
+		code.WriteString("\tif err != nil {\n")
+		code.WriteString(fmt.Sprintf("\t\treturn fmt.Errorf(\"无效的 %s: %%q\", text)\n", e.Name))
+		code.WriteString("\t}\n")
+		code.WriteString(fmt.Sprintf("\t*e = %s(n)\n\treturn nil\n}\n\n", e.Name))
+
+		code.WriteString("// MarshalJSON 序列化为数字，与数据库中的取值一致\n")
+		code.WriteString(fmt.Sprintf("func (e %s) MarshalJSON() ([]byte, error) {\n", e.Name))
+		code.WriteString(fmt.Sprintf("\treturn []byte(%s), nil\n}\n\n", format))
+
+		code.WriteString("// UnmarshalJSON 解析数字或标签字符串\n")
+		code.WriteString(fmt.Sprintf("func (e *%s) UnmarshalJSON(data []byte) error {\n", e.Name))
+		code.WriteString("\tvar label string\n")
+		code.WriteString("\tif err := json.Unmarshal(data, &label); err == nil {\n")
+		code.WriteString("\t\treturn e.UnmarshalText([]byte(label))\n\t}\n")
+		code.WriteString(fmt.Sprintf("\tvar n %s\n", e.Base))
+		code.WriteString("\tif err := json.Unmarshal(data, &n); err != nil {\n")
+		code.WriteString(fmt.Sprintf("\t\treturn fmt.Errorf(\"无效的 %s: %%s\", data)\n", e.Name))
+		code.WriteString("\t}\n")
+		code.WriteString(fmt.Sprintf("\t*e = %s(n)\n\treturn nil\n}\n", e.Name))
+	}
+	return code.String()
+}
+
+// enumFieldCode 生成查询包中以枚举类型为参数的字段
+func enumFieldCode(modelPkg, fieldType string, e enumType) string {
+	enum := modelPkg + "." + e.Name
+	var code strings.Builder
+	code.WriteString(fmt.Sprintf("\n// %s %s 字段，比较和赋值使用 %s\n", fieldType, e.Column, enum))
+	code.WriteString(fmt.Sprintf("type %s struct {\n\tfield.%s\n}\n\n", fieldType, e.GenType))
+
+	code.WriteString(fmt.Sprintf("func new%s(table, column string) %s {\n", strings.Title(fieldType), fieldType))
+	code.WriteString(fmt.Sprintf("\treturn %s{field.New%s(table, column)}\n}\n\n", fieldType, e.GenType))
+
+	for _, op := range []string{"Eq", "Neq"} {
+		code.WriteString(fmt.Sprintf("func (f %s) %s(value %s) field.Expr {\n", fieldType, op, enum))
+		code.WriteString(fmt.Sprintf("\treturn f.%s.%s(%s(value))\n}\n\n", e.GenType, op, e.Base))
+	}
+	for _, op := range []string{"In", "NotIn"} {
+		code.WriteString(fmt.Sprintf("func (f %s) %s(values ...%s) field.Expr {\n", fieldType, op, enum))
+		code.WriteString(fmt.Sprintf("\traw := make([]%s, len(values))\n", e.Base))
+		code.WriteString(fmt.Sprintf("\tfor i, value := range values {\n\t\traw[i] = %s(value)\n\t}\n", e.Base))
+		code.WriteString(fmt.Sprintf("\treturn f.%s.%s(raw...)\n}\n\n", e.GenType, op))
+	}
+	code.WriteString(fmt.Sprintf("func (f %s) Value(value %s) field.AssignExpr {\n", fieldType, enum))
+	code.WriteString(fmt.Sprintf("\treturn f.%s.Value(%s(value))\n}\n", e.GenType, e.Base))
+	return code.String()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseEnumComment(t *testing.T) {
+	tests := []struct {
+		comment string
+		want    []EnumValue // nil 表示不是枚举
+	}{
+		{"0关闭 1显示", []EnumValue{{Value: 0, Label: "关闭"}, {Value: 1, Label: "显示"}}},
+		{"0待付款  1已付款 2已关闭", []EnumValue{{Value: 0, Label: "待付款"}, {Value: 1, Label: "已付款"}, {Value: 2, Label: "已关闭"}}},
+		{"0支付宝,1银行卡", []EnumValue{{Value: 0, Label: "支付宝"}, {Value: 1, Label: "银行卡"}}},
+		{"1开启  0关闭", []EnumValue{{Value: 1, Label: "开启"}, {Value: 0, Label: "关闭"}}},
+		{"1是充值成功；0是未充值只点进来过；", []EnumValue{{Value: 1, Label: "是充值成功"}, {Value: 0, Label: "是未充值只点进来过"}}},
+		{"1：微信支付；2：支付宝", []EnumValue{{Value: 1, Label: "微信支付"}, {Value: 2, Label: "支付宝"}}},
+		{"1-比赛获得 2-兑换 3-比赛领奖", []EnumValue{{Value: 1, Label: "比赛获得"}, {Value: 2, Label: "兑换"}, {Value: 3, Label: "比赛领奖"}}},
+		{"0网站加分,1捕鸟,2连线,3赠送,4兑换,528game,6领取", []EnumValue{
+			{Value: 0, Label: "网站加分"}, {Value: 1, Label: "捕鸟"}, {Value: 2, Label: "连线"}, {Value: 3, Label: "赠送"},
+			{Value: 4, Label: "兑换"}, {Value: 5, Label: "28game"}, {Value: 6, Label: "领取"},
+		}},
+		{"-1禁用,0正常", []EnumValue{{Value: -1, Label: "禁用"}, {Value: 0, Label: "正常"}}},
+
+		// 前缀说明
+		{"状态 0:等待 1:进行中 2:结束", []EnumValue{{Value: 0, Label: "等待"}, {Value: 1, Label: "进行中"}, {Value: 2, Label: "结束"}}},
+		{"结果 0:输 1:赢 2:和", []EnumValue{{Value: 0, Label: "输"}, {Value: 1, Label: "赢"}, {Value: 2, Label: "和"}}},
+		{"是否含水位的游戏 1是  0不是", []EnumValue{{Value: 1, Label: "是"}, {Value: 0, Label: "不是"}}},
+		{"状态:0未处理,1已处理", []EnumValue{{Value: 0, Label: "未处理"}, {Value: 1, Label: "已处理"}}},
+		{"类型:1:会员 2:管理员", []EnumValue{{Value: 1, Label: "会员"}, {Value: 2, Label: "管理员"}}},
+		{"道具ID 1礼品券 2喇叭", []EnumValue{{Value: 1, Label: "礼品券"}, {Value: 2, Label: "喇叭"}}},
+		{"房间类型1 1倍房 2 5倍房", []EnumValue{{Value: 1, Label: "1倍房"}, {Value: 2, Label: "5倍房"}}},
+		{"状态(0：新  1：充值成功)", []EnumValue{{Value: 0, Label: "新"}, {Value: 1, Label: "充值成功"}}},
+		{"入帐标志（0：默认  1：已入帐  9:异常）", []EnumValue{{Value: 0, Label: "默认"}, {Value: 1, Label: "已入帐"}, {Value: 9, Label: "异常"}}},
+		{"状态:-1禁用,0正常", []EnumValue{{Value: -1, Label: "禁用"}, {Value: 0, Label: "正常"}}},
+
+		// 不是枚举
+		{"", nil},
+		{"用户ID", nil},
+		{"房间ID,0为大厅", nil},
+		{"0表示正常充值，1表示是促销活动，免费赠送", nil},
+		{"备注, 最多200字, 300字符", nil},
+		{"1-5", nil},
+		{"0关闭 0显示", nil},
+		{"状态 0:", nil},
+	}
+	for _, tt := range tests {
+		got, ok := parseEnumComment(tt.comment)
+		if ok != (tt.want != nil) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseEnumComment(%q) = %+v, %v, want %+v", tt.comment, got, ok, tt.want)
+		}
+	}
+}
+
+func TestSplitEnumItems(t *testing.T) {
+	tests := []struct {
+		comment string
+		want    []string
+	}{
+		{"0支付宝,1银行卡", []string{"0支付宝", "1银行卡"}},
+		{"0未处理，1已处理、2冻结", []string{"0未处理", "1已处理", "2冻结"}},
+		{"1是充值成功；0是未充值只点进来过；", []string{"1是充值成功", "0是未充值只点进来过"}},
+		{"1：微信支付;2：支付宝", []string{"1：微信支付", "2：支付宝"}},
+		{"0待付款  1已付款 2已关闭", []string{"0待付款", "1已付款", "2已关闭"}},
+		{"0:等待 1:进行中 2:结束", []string{"0:等待", "1:进行中", "2:结束"}},
+		{"1 1倍房 2 5倍房", []string{"1 1倍房", "2 5倍房"}},
+		{"1-比赛获得 2-兑换", []string{"1-比赛获得", "2-兑换"}},
+		{"0, ,1", []string{"0", "1"}},
+		{"用户ID", []string{"用户ID"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := splitEnumItems(tt.comment); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitEnumItems(%q) = %q, want %q", tt.comment, got, tt.want)
+		}
+	}
+}
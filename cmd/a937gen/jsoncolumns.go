@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gorm.io/gen"
+)
+
+// jsonColumn 为一列生成的 JSON 字段
+type jsonColumn struct {
+	Column string // 列名
+	Field  string // 模型字段名
+	Name   string // 模型包中的类型别名，如 UseraccountGameDict
+	Type   string // JSON 解析出的 Go 类型，如 map[string]interface{}
+}
+
+// jsonFile 一个表生成的 JSON 字段
+type jsonFile struct {
+	FileName    string
+	QueryStruct string
+	Columns     []jsonColumn
+}
+
+// jsonType 返回匹配 json_columns 的 Go 类型，键为 "表名.列名"，支持通配符
+// 多个键匹配时取排序后的第一个，保证生成结果稳定
+func jsonType(jsonColumns map[string]string, table, column string) (string, bool) {
+	keys := make([]string, 0, len(jsonColumns))
+	for key := range jsonColumns {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if matchColumn(key, table, column) {
+			return jsonColumns[key], true
+		}
+	}
+	return "", false
+}
+
+// jsonOpt 返回把 json_columns 中的字符串列替换为 types.JSON 的模型选项，生成的字段追加到 columns
+// 模型字段使用模型包中的类型别名，Go 类型可以引用模型包中手写的结构体
+func jsonOpt(dbConfig DatabaseConfig, table, structName string, columns *[]jsonColumn) gen.ModelOpt {
+	return gen.FieldModify(func(f gen.Field) gen.Field {
+		goType, ok := jsonType(dbConfig.JSONColumns, table, f.ColumnName)
+		if !ok {
+			return f
+		}
+		base := strings.TrimLeft(f.Type, "*")
+		if base != "string" && base != "[]byte" {
+			fmt.Printf("警告: %s.%s 的类型 %s 不能作为 JSON\n", table, f.ColumnName, f.Type)
+			return f
+		}
+
+		fieldName := fieldNaming.SchemaName(f.ColumnName)
+		column := jsonColumn{
+			Column: f.ColumnName,
+			Field:  fieldName,
+			Name:   structName + fieldName,
+			Type:   goType,
+		}
+		*columns = append(*columns, column)
+
+		f.Type = f.Type[:len(f.Type)-len(base)] + column.Name
+		return f
+	})
+}
+
+// generateJSONFiles 生成表的 JSON 类型别名（模型包）和支持 JSON 查询的查询字段（查询包）
+func generateJSONFiles(dbConfig DatabaseConfig, fileName, queryStruct string, columns []jsonColumn) error {
+	if len(columns) == 0 {
+		return nil
+	}
+	modelPkg := filepath.Base(filepath.Clean(dbConfig.modelPath()))
+
+	// Go 类型可能引用标准库（如 json.RawMessage），交给 goimports 补全导入
+	modelFile := filepath.Join(dbConfig.modelPath(), fileName+"_json.gen.go")
+	if err := writeGoFileImports(modelFile, jsonModelCode(modelPkg, columns)); err != nil {
+		return err
+	}
+
+	queryFile := filepath.Join(dbConfig.OutPath, fileName+".gen.go")
+	data, err := os.ReadFile(queryFile)
+	if err != nil {
+		return fmt.Errorf("读取查询文件失败: %v", err)
+	}
+	code := addImports(string(data), "gorm.io/datatypes")
+	for _, c := range columns {
+		fieldType := queryStruct + c.Field + "Field"
+		code = replaceQueryField(code, c.Field, c.Column, "Field", fieldType)
+		code += jsonFieldCode(modelPkg, fieldType, c)
+	}
+	return writeGoFile(queryFile, code)
+}
+
+// addImports 在 gorm/gen 生成的导入列表中追加导入
+func addImports(code string, paths ...string) string {
+	var lines strings.Builder
+	for _, path := range paths {
+		if !strings.Contains(code, fmt.Sprintf("%q", path)) {
+			lines.WriteString(fmt.Sprintf("\t%q\n", path))
+		}
+	}
+	return strings.Replace(code, "import (\n", "import (\n"+lines.String(), 1)
+}
+
+// jsonModelCode 生成模型包中的类型别名
+func jsonModelCode(pkg string, columns []jsonColumn) string {
+	var code strings.Builder
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n")
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n")
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n\n")
+	code.WriteString(fmt.Sprintf("package %s\n\n", pkg))
+	code.WriteString(fmt.Sprintf("import %q\n", typesPkgPath))
+
+	for _, c := range columns {
+		code.WriteString(fmt.Sprintf("\n// %s %s 列中的 JSON 数据\n", c.Name, c.Column))
+		code.WriteString(fmt.Sprintf("type %s = types.JSON[%s]\n", c.Name, c.Type))
+	}
+	return code.String()
+}
+
+// jsonFieldCode 生成查询包中以 JSON 类型为参数、支持 JSON_EXTRACT 查询的字段
+func jsonFieldCode(modelPkg, fieldType string, c jsonColumn) string {
+	value := modelPkg + "." + c.Name
+	var code strings.Builder
+	code.WriteString(fmt.Sprintf("\n// %s %s 字段，比较和赋值使用 %s，支持按 JSON 路径查询\n", fieldType, c.Column, value))
+	code.WriteString(fmt.Sprintf("type %s struct {\n\tfield.Field\n\n", fieldType))
+	code.WriteString("\tcolumn string // 带表名的列名，用于 JSON 查询\n}\n\n")
+
+	code.WriteString(fmt.Sprintf("func new%s(table, column string) %s {\n", strings.Title(fieldType), fieldType))
+	code.WriteString(fmt.Sprintf("\treturn %s{Field: field.NewField(table, column), column: table + \".\" + column}\n}\n\n", fieldType))
+
+	for _, op := range []string{"Eq", "Neq"} {
+		code.WriteString(fmt.Sprintf("func (f %s) %s(value %s) field.Expr {\n", fieldType, op, value))
+		code.WriteString(fmt.Sprintf("\treturn f.Field.%s(value)\n}\n\n", op))
+	}
+	code.WriteString(fmt.Sprintf("func (f %s) Value(value %s) field.AssignExpr {\n", fieldType, value))
+	code.WriteString("\treturn f.Field.Value(value)\n}\n\n")
+
+	code.WriteString("// HasKey 条件 JSON_EXTRACT(列, '$.k1.k2') IS NOT NULL\n")
+	code.WriteString(fmt.Sprintf("func (f %s) HasKey(keys ...string) gen.Condition {\n", fieldType))
+	code.WriteString("\treturn gen.Cond(datatypes.JSONQuery(f.column).HasKey(keys...))[0]\n}\n\n")
+
+	code.WriteString("// Equals 条件 JSON_EXTRACT(列, '$.k1.k2') = value\n")
+	code.WriteString(fmt.Sprintf("func (f %s) Equals(value interface{}, keys ...string) gen.Condition {\n", fieldType))
+	code.WriteString("\treturn gen.Cond(datatypes.JSONQuery(f.column).Equals(value, keys...))[0]\n}\n\n")
+
+	code.WriteString("// Contains 条件 JSON_CONTAINS(列, JSON_ARRAY(value))，用于 JSON 数组\n")
+	code.WriteString(fmt.Sprintf("func (f %s) Contains(value interface{}) gen.Condition {\n", fieldType))
+	code.WriteString("\treturn gen.Cond(datatypes.JSONArrayQuery(f.column).Contains(value))[0]\n}\n")
+	return code.String()
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// command 子命令
+type command struct {
+	Name  string
+	Usage string
+	Run   func(args []string) error
+}
+
+var commands = []command{
+	{Name: "scan", Usage: "扫描 MySQL 服务器上的数据库、表和存储过程；scan drift 检查模型与表结构是否一致", Run: runScan},
+	{Name: "models", Usage: "生成数据库模型和查询代码", Run: runModels},
+	{Name: "procedures", Usage: "生成存储过程包装方法", Run: runProcedures},
+	{Name: "all", Usage: "依次执行 models 和 procedures", Run: runAll},
+}
+
+// 退出码
+const (
+	exitOK      = 0
+	exitFailure = 1 // 有数据库处理失败
+	exitUsage   = 2 // 命令行参数错误
+	exitDrift   = 3 // -dry-run 发现生成结果与现有文件不同，或 scan drift 发现模型与表结构不一致
+)
+
+// errUsage 表示命令行参数错误，已输出帮助信息
+var errUsage = errors.New("usage")
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run 执行子命令并返回退出码
+func run(args []string) int {
+	if len(args) == 0 {
+		printUsage()
+		return exitUsage
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "-help" || name == "--help" {
+		if len(args) > 1 {
+			if cmd := findCommand(args[1]); cmd != nil {
+				return exitCode(cmd.Run([]string{"-h"}))
+			}
+		}
+		printUsage()
+		return exitOK
+	}
+
+	cmd := findCommand(name)
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "未知命令: %s\n\n", name)
+		printUsage()
+		return exitUsage
+	}
+	return exitCode(cmd.Run(args[1:]))
+}
+
+// exitCode 将子命令返回的错误转换为退出码
+func exitCode(err error) int {
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, errUsage):
+		return exitUsage
+	case errors.Is(err, errDrift):
+		fmt.Fprintf(os.Stderr, "\n%v\n", err)
+		return exitDrift
+	default:
+		fmt.Fprintf(os.Stderr, "\n错误: %v\n", err)
+		return exitFailure
+	}
+}
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].Name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "使用方法:")
+	fmt.Fprintln(os.Stderr, "  a937gen <命令> [参数]")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "命令:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", cmd.Name, cmd.Usage)
+	}
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "使用 \"a937gen <命令> -h\" 查看命令的参数")
+}
+
+// newFlagSet 创建子命令的参数集合，-h 时输出命令说明和参数列表
+func newFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "使用方法: a937gen %s\n\n参数:\n", usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags 解析子命令参数，参数错误统一返回 errUsage
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return errUsage
+}
+
+// configFlags 读取配置文件的子命令共用的参数
+type configFlags struct {
+	config   string
+	db       string
+	snapshot string
+}
+
+func (f *configFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.config, "config", "databases.yml", "配置文件路径")
+	fs.StringVar(&f.db, "db", "", "只处理指定的数据库，多个用逗号分隔（不区分大小写）")
+	fs.StringVar(&f.snapshot, "snapshot", "", "从目录中的表结构快照 <schema>.json（由 scan -snapshot 导出）或 mysqldump 导出的 <schema>.sql 读取表结构，不连接数据库")
+}
+
+// load 加载配置文件并按 -db 筛选数据库，指定了 -snapshot 时改为读取快照
+func (f *configFlags) load() (*Config, []DatabaseConfig, error) {
+	config, err := loadConfig(f.config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("加载配置文件失败: %v", err)
+	}
+	databases, err := config.selectDatabases(f.db)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := useSnapshots(databases, f.snapshot); err != nil {
+		return nil, nil, err
+	}
+	fmt.Printf("从配置文件 %s 加载了 %d 个数据库配置\n", f.config, len(databases))
+	return config, databases, nil
+}
+
+// jobsFlag 注册同时处理的数据库数量参数
+func jobsFlag(fs *flag.FlagSet) *int {
+	return fs.Int("jobs", defaultJobs, "同时处理的数据库数量")
+}
+
+// checkJobs 检查 -jobs 参数
+func checkJobs(fs *flag.FlagSet, jobs int) error {
+	if jobs < 1 {
+		fmt.Fprintf(fs.Output(), "-jobs 必须大于 0\n")
+		return errUsage
+	}
+	return nil
+}
+
+// failures 收集处理失败的数据库
+type failures map[string]error
+
+func (f failures) add(name string, err error) {
+	if _, ok := f[name]; !ok {
+		f[name] = err
+	}
+}
+
+// err 汇总失败的数据库，全部成功时返回 nil
+func (f failures) err(action string) error {
+	if len(f) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("%d 个数据库%s失败: %s", len(names), action, strings.Join(names, ", "))
+}
+
+// runAll 依次生成模型和存储过程包装方法
+func runAll(args []string) error {
+	fs := newFlagSet("all", "all [参数]")
+	var cf configFlags
+	cf.register(fs)
+	jobs := jobsFlag(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkJobs(fs, *jobs); err != nil {
+		return err
+	}
+
+	config, databases, err := cf.load()
+	if err != nil {
+		return err
+	}
+
+	failed := failures{}
+	generateModels(databases, config.Global, *jobs, failed)
+	for _, dbConfig := range databases {
+		if _, ok := failed[dbConfig.Name]; ok {
+			continue
+		}
+		fmt.Printf("\n正在生成数据库 %s 的存储过程包装方法...\n", dbConfig.Name)
+		if err := generateProcedures(dbConfig, config.Global); err != nil {
+			fmt.Fprintf(os.Stderr, "生成数据库 %s 的存储过程失败: %v\n", dbConfig.Name, err)
+			failed.add(dbConfig.Name, err)
+			continue
+		}
+		fmt.Printf("数据库 %s 的存储过程包装方法生成完成！\n", dbConfig.Name)
+	}
+	return failed.err("生成")
+}
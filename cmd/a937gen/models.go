@@ -0,0 +1,395 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+)
+
+// runModels 生成数据库模型和查询代码
+func runModels(args []string) error {
+	fs := newFlagSet("models", "models [参数] [数据库名...]")
+	var cf configFlags
+	cf.register(fs)
+	fromEnv := fs.Bool("env", false, "从 DB_DSN_<NAME> / DB_TABLES_<NAME> 环境变量读取数据库配置，而不是配置文件")
+	jobs := jobsFlag(fs)
+	dryRun := fs.Bool("dry-run", false, "生成到临时目录并与现有文件比较，输出差异但不修改现有文件；有差异时退出码为 3")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkJobs(fs, *jobs); err != nil {
+		return err
+	}
+
+	var databases, allDatabases []DatabaseConfig
+	var globalConfig GlobalConfig
+	if *fromEnv {
+		var err error
+		databases, err = databasesFromEnv(fs.Args())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("从环境变量加载了 %d 个数据库配置\n", len(databases))
+		if err := useSnapshots(databases, cf.snapshot); err != nil {
+			return err
+		}
+		globalConfig = GlobalConfig{GenOptions: GenOptions{
+			FieldWithIndexTag: boolPtr(true),
+			FieldWithTypeTag:  boolPtr(true),
+			FieldSignable:     boolPtr(true),
+			FieldNullable:     boolPtr(true),
+		}}
+	} else {
+		if fs.NArg() > 0 {
+			fmt.Fprintf(fs.Output(), "数据库名参数只能与 -env 一起使用，配置文件方式请使用 -db\n")
+			return errUsage
+		}
+		config, selected, err := cf.load()
+		if err != nil {
+			return err
+		}
+		databases, allDatabases, globalConfig = selected, config.Databases, config.Global
+	}
+
+	if *dryRun {
+		if allDatabases == nil {
+			allDatabases = databases
+		}
+		return dryRunModels(databases, allDatabases, globalConfig, *jobs)
+	}
+
+	failed := failures{}
+	generateModels(databases, globalConfig, *jobs, failed)
+	if err := failed.err("生成模型"); err != nil {
+		return err
+	}
+	fmt.Println("\n所有数据库模型生成完成！")
+	return nil
+}
+
+// generateModels 最多同时处理 jobs 个数据库，生成所有数据库的模型并输出汇总表，失败的数据库记录到 failed
+func generateModels(databases []DatabaseConfig, globalConfig GlobalConfig, jobs int, failed failures) {
+	begin := time.Now()
+	results := make([]databaseResult, len(databases))
+	for i, dbConfig := range databases {
+		results[i].Name = dbConfig.Name
+	}
+	defer func() {
+		printSummary(results, time.Since(begin))
+		for _, result := range results {
+			if result.Err != nil {
+				failed.add(result.Name, result.Err)
+			}
+		}
+	}()
+
+	// 连接数据库并确定每个数据库要生成的表
+	plans := make([]*databasePlan, len(databases))
+	errs := runParallel(len(databases), jobs, func(i int) error {
+		start := time.Now()
+		defer func() { results[i].Duration += time.Since(start) }()
+		plan, err := planDatabase(databases[i], globalConfig)
+		if err != nil {
+			return err
+		}
+		plans[i] = plan
+		results[i].Tables = len(plan.Tables)
+		return nil
+	})
+	var planned []databasePlan
+	for i, err := range errs {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "准备数据库 %s 失败: %v\n", databases[i].Name, err)
+			results[i].Err = err
+			continue
+		}
+		planned = append(planned, *plans[i])
+	}
+
+	// 检查是否有多个数据库向同一个模型包写入同名结构体
+	if err := checkModelCollisions(planned); err != nil {
+		fmt.Fprintf(os.Stderr, "模型冲突检查失败: %v\n", err)
+		for i, plan := range plans {
+			if plan != nil {
+				closeDB(plan.DB)
+				results[i].Err = err
+			}
+		}
+		return
+	}
+
+	// 生成所有数据库的模型，完成后关闭连接
+	errs = runParallel(len(databases), jobs, func(i int) error {
+		plan := plans[i]
+		if plan == nil {
+			return nil
+		}
+		defer closeDB(plan.DB)
+		start := time.Now()
+		dirs := []string{plan.Config.OutPath, plan.Config.modelPath()}
+		before := readFileTimes(dirs...)
+		defer func() {
+			results[i].Duration += time.Since(start)
+			results[i].Files = before.written(dirs...)
+		}()
+
+		fmt.Printf("\n正在生成数据库 %s 的模型...\n", plan.Config.Name)
+		if err := generateDatabase(*plan, globalConfig); err != nil {
+			return err
+		}
+		fmt.Printf("数据库 %s 的模型生成完成！\n", plan.Config.Name)
+		return nil
+	})
+	for i, err := range errs {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "生成数据库 %s 失败: %v\n", databases[i].Name, err)
+			results[i].Err = err
+		}
+	}
+}
+
+// databasePlan 单个数据库的生成计划
+type databasePlan struct {
+	Config DatabaseConfig
+	DB     *gorm.DB
+	Tables []string     // 需要生成模型的表，已合并的分表不在其中
+	Shards []shardGroup // 合并到基础表模型的分表
+
+	Relations []relation // 表之间的关联
+
+	Comments map[string]map[string]string // 表名 -> 列名 -> 修复后的注释
+}
+
+// planDatabase 连接数据库（或打开快照）并确定需要生成的表，失败时关闭连接
+func planDatabase(dbConfig DatabaseConfig, globalConfig GlobalConfig) (plan *databasePlan, err error) {
+	// 连接数据库或打开表结构快照
+	db, err := openDatabase(&dbConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			closeDB(db)
+		}
+	}()
+	printSnapshotSource(dbConfig, db)
+
+	// 获取表名
+	var tables []string
+	if len(dbConfig.Tables) > 0 {
+		// 使用指定的表名
+		tables = dbConfig.Tables
+		fmt.Printf("数据库 %s 使用指定的表: %v\n", dbConfig.Name, tables)
+	} else {
+		// 获取所有表名
+		allTables, err := db.Migrator().GetTables()
+		if err != nil {
+			return nil, fmt.Errorf("获取表列表失败: %v", err)
+		}
+		tables = allTables
+		fmt.Printf("数据库 %s 找到 %d 个表: %v\n", dbConfig.Name, len(tables), tables)
+	}
+
+	// 合并结构相同的分表
+	shards, tables, err := detectShards(db, dbConfig, tables)
+	if err != nil {
+		return nil, err
+	}
+
+	// 修复按 GBK/latin1 等字符集读出的乱码注释
+	schema, err := dbConfig.schemaName()
+	if err != nil {
+		return nil, err
+	}
+	comments, err := columnComments(db, schema, dbConfig.CommentCharset)
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) > 0 {
+		fmt.Printf("数据库 %s 有 %d 个表的列注释需要转码\n", dbConfig.Name, len(comments))
+	}
+
+	// 合并配置的关联和外键约束
+	discover := boolValue(globalConfig.optionsFor(dbConfig).DiscoverRelations)
+	relations, err := resolveRelations(db, dbConfig, discover, tables)
+	if err != nil {
+		return nil, err
+	}
+	if len(relations) > 0 {
+		fmt.Printf("数据库 %s 有 %d 个关联\n", dbConfig.Name, len(relations))
+	}
+
+	return &databasePlan{Config: dbConfig, DB: db, Tables: tables, Shards: shards, Relations: relations, Comments: comments}, nil
+}
+
+// checkModelCollisions 检查不同数据库是否会向同一个模型包写入同名结构体
+func checkModelCollisions(plans []databasePlan) error {
+	// 模型包目录 -> 结构体名 -> 数据库.表
+	owners := make(map[string]map[string]string)
+	var conflicts []string
+
+	for _, plan := range plans {
+		pkgPath, err := filepath.Abs(plan.Config.modelPath())
+		if err != nil {
+			return fmt.Errorf("解析数据库 %s 的模型包路径失败: %v", plan.Config.Name, err)
+		}
+		if owners[pkgPath] == nil {
+			owners[pkgPath] = make(map[string]string)
+		}
+
+		for _, table := range plan.Tables {
+			structName := plan.DB.Config.NamingStrategy.SchemaName(table)
+			owner := fmt.Sprintf("%s.%s", plan.Config.Name, table)
+			if prev, ok := owners[pkgPath][structName]; ok && !strings.HasPrefix(prev, plan.Config.Name+".") {
+				conflicts = append(conflicts, fmt.Sprintf("%s: %s 与 %s 都会生成结构体 %s", pkgPath, prev, owner, structName))
+				continue
+			}
+			owners[pkgPath][structName] = owner
+		}
+	}
+
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return fmt.Errorf("发现 %d 处模型冲突，请为这些数据库配置不同的 model_pkg_path:\n  %s",
+			len(conflicts), strings.Join(conflicts, "\n  "))
+	}
+	return nil
+}
+
+// generateDatabase 生成指定数据库的模型
+func generateDatabase(plan databasePlan, globalConfig GlobalConfig) error {
+	dbConfig := plan.Config
+
+	// 创建输出目录
+	err := os.MkdirAll(dbConfig.OutPath, 0755)
+	if err != nil {
+		return fmt.Errorf("创建输出目录失败: %v", err)
+	}
+
+	// 创建生成器
+	options := globalConfig.optionsFor(dbConfig)
+	genConfig, err := options.genConfig()
+	if err != nil {
+		return err
+	}
+	genConfig.OutPath = dbConfig.OutPath
+	genConfig.ModelPkgPath = dbConfig.modelPath()
+	genConfig.WithDataTypeMap(dataTypeMap(options))
+	genConfig.WithImportPkgPath(typesPkgPath)
+	g := gen.NewGenerator(genConfig)
+
+	// 设置数据库连接
+	g.UseDB(plan.DB)
+
+	tables := plan.Tables
+	if len(tables) == 0 {
+		fmt.Printf("数据库 %s 中没有找到表\n", dbConfig.Name)
+		return nil
+	}
+
+	// 先为关联涉及的表生成不带关联字段的模型，作为关联字段的类型
+	relate := make(map[string]relateFunc)
+	fieldNames := make(map[string]map[string]bool)
+	for _, table := range relatedTables(plan.Relations) {
+		meta := g.GenerateModel(table)
+		relate[table] = func(relationship field.RelationshipType, fieldName string, config *field.RelateConfig) gen.ModelOpt {
+			return gen.FieldRelate(relationship, fieldName, meta, config)
+		}
+		fieldNames[table] = make(map[string]bool)
+		for _, f := range meta.Fields {
+			fieldNames[table][f.Name] = true
+		}
+	}
+
+	// 生成所有表的模型，记录分表基础表对应的查询结构体名
+	var models []interface{}
+	queryStructs := make(map[string]string)
+	var enumFiles []enumFile
+	var jsonFiles []jsonFile
+	var groupFiles []groupFile
+	unixTimeColumns := globalConfig.unixTimeColumns(dbConfig)
+	decimalColumns := globalConfig.decimalColumns(dbConfig)
+	for _, table := range tables {
+		var enums []enumType
+		var jsonColumns []jsonColumn
+		var fields []modelField
+		opts := commentOpts(plan.Comments[table])
+		opts = append(opts, columnTypeOpts(dbConfig, table)...)
+		opts = append(opts, unixTimeOpt(unixTimeColumns, table))
+		opts = append(opts, decimalOpt(decimalColumns, table))
+		structName := plan.DB.Config.NamingStrategy.SchemaName(table)
+		opts = append(opts, jsonOpt(dbConfig, table, structName, &jsonColumns))
+		opts = append(opts, enumOpt(dbConfig, table, structName, &enums))
+		opts = append(opts, fieldsOpt(&fields))
+		opts = append(opts, relationOpts(table, plan.Relations, relate, fieldNames[table])...)
+		meta := g.GenerateModel(table, opts...)
+		queryStructs[table] = meta.QueryStructName
+		models = append(models, meta)
+		if len(enums) > 0 {
+			enumFiles = append(enumFiles, enumFile{FileName: meta.FileName, QueryStruct: meta.QueryStructName, Enums: enums})
+		}
+		if len(jsonColumns) > 0 {
+			jsonFiles = append(jsonFiles, jsonFile{FileName: meta.FileName, QueryStruct: meta.QueryStructName, Columns: jsonColumns})
+		}
+		if groups := columnGroups(dbConfig, table, meta.ModelStructName, fields); len(groups) > 0 {
+			groupFiles = append(groupFiles, groupFile{FileName: meta.FileName, ModelStruct: meta.ModelStructName, Groups: groups})
+		}
+	}
+
+	// 应用模型
+	g.ApplyBasic(models...)
+
+	// 执行生成
+	if err := execute(g); err != nil {
+		return err
+	}
+
+	// 生成枚举类型
+	for _, file := range enumFiles {
+		if err := generateEnumFiles(dbConfig, file.FileName, file.QueryStruct, file.Enums); err != nil {
+			return fmt.Errorf("生成 %s 的枚举类型失败: %v", file.FileName, err)
+		}
+	}
+
+	// 生成 JSON 字段
+	for _, file := range jsonFiles {
+		if err := generateJSONFiles(dbConfig, file.FileName, file.QueryStruct, file.Columns); err != nil {
+			return fmt.Errorf("生成 %s 的 JSON 字段失败: %v", file.FileName, err)
+		}
+	}
+
+	// 生成编号列分组方法
+	for _, file := range groupFiles {
+		if err := generateColumnGroupFile(dbConfig, file.FileName, file.ModelStruct, file.Groups); err != nil {
+			return fmt.Errorf("生成 %s 的列分组方法失败: %v", file.FileName, err)
+		}
+	}
+
+	// 生成分表路由方法
+	for _, group := range plan.Shards {
+		if err := generateShardFile(dbConfig, group, queryStructs[group.Config.Table]); err != nil {
+			return fmt.Errorf("生成分表 %s 的路由方法失败: %v", group.Config.Table, err)
+		}
+	}
+
+	fmt.Printf("生成的文件位于: %s/ (模型: %s/)\n", dbConfig.OutPath, dbConfig.modelPath())
+	return nil
+}
+
+// execute 执行生成，gorm/gen 出错时会 panic，这里转换为错误以免中断其它数据库
+func execute(g *gen.Generator) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("生成代码失败: %v", r)
+		}
+	}()
+	g.Execute()
+	return nil
+}
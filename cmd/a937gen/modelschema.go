@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// modelColumn 模型结构体中映射到列的字段，从 gorm 标签读取
+type modelColumn struct {
+	Name    string // 列名
+	Field   string // 字段名
+	GoType  string // 字段类型，如 *int32、types.Decimal
+	DBType  string // gorm 标签中的 type，如 int(11)
+	NotNull bool   // gorm 标签中有 not null 或 primaryKey
+}
+
+// signature 返回用于比较的列类型描述，如 "*int32 int(11)"、"string varchar(32) not null"
+func (c modelColumn) signature() string {
+	s := c.GoType
+	if c.DBType != "" {
+		s += " " + c.DBType
+	}
+	if c.NotNull {
+		s += " not null"
+	}
+	return s
+}
+
+// modelTable 模型包中的一个结构体
+type modelTable struct {
+	Name    string // 表名，TableName 方法的返回值
+	Struct  string
+	File    string
+	Columns []modelColumn // 按字段顺序
+}
+
+// column 按列名查找列，列名不区分大小写
+func (t modelTable) column(name string) (modelColumn, bool) {
+	for _, c := range t.Columns {
+		if strings.EqualFold(c.Name, name) {
+			return c, true
+		}
+	}
+	return modelColumn{}, false
+}
+
+// readModelTables 解析模型包目录中的 Go 文件，返回有 TableName 方法的结构体，键为表名
+// 目录不存在时返回空结果；没有 column 标签的字段（如关联字段）和 gorm:"-" 字段不计入列
+func readModelTables(dir string) (map[string]modelTable, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return map[string]modelTable{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取模型目录失败: %v", err)
+	}
+
+	fset := token.NewFileSet()
+	consts := make(map[string]string)       // 字符串常量
+	structs := make(map[string]modelTable)  // 结构体名 -> 字段
+	tableNames := make(map[string]ast.Expr) // 结构体名 -> TableName 返回的表达式
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("解析模型文件失败: %v", err)
+		}
+
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.ValueSpec:
+						for i, ident := range spec.Names {
+							if i < len(spec.Values) {
+								if value, ok := stringLiteral(spec.Values[i]); ok {
+									consts[ident.Name] = value
+								}
+							}
+						}
+					case *ast.TypeSpec:
+						if st, ok := spec.Type.(*ast.StructType); ok {
+							structs[spec.Name.Name] = modelTable{Struct: spec.Name.Name, File: name, Columns: structColumns(st)}
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Name.Name != "TableName" || decl.Recv == nil || len(decl.Recv.List) != 1 || decl.Body == nil {
+					continue
+				}
+				recv := strings.TrimPrefix(types.ExprString(decl.Recv.List[0].Type), "*")
+				for _, stmt := range decl.Body.List {
+					if ret, ok := stmt.(*ast.ReturnStmt); ok && len(ret.Results) == 1 {
+						tableNames[recv] = ret.Results[0]
+					}
+				}
+			}
+		}
+	}
+
+	tables := make(map[string]modelTable)
+	for structName, expr := range tableNames {
+		table, ok := structs[structName]
+		if !ok {
+			continue
+		}
+		name, ok := stringLiteral(expr)
+		if ident, isIdent := expr.(*ast.Ident); isIdent {
+			name, ok = consts[ident.Name]
+		}
+		if !ok {
+			continue
+		}
+		table.Name = name
+		tables[name] = table
+	}
+	return tables, nil
+}
+
+// structColumns 返回结构体中带 gorm column 标签的字段
+func structColumns(st *ast.StructType) []modelColumn {
+	var columns []modelColumn
+	for _, f := range st.Fields.List {
+		if f.Tag == nil || len(f.Names) == 0 {
+			continue
+		}
+		tag, err := strconv.Unquote(f.Tag.Value)
+		if err != nil {
+			continue
+		}
+		settings := gormSettings(reflect.StructTag(tag).Get("gorm"))
+		column, ok := settings["COLUMN"]
+		if !ok || settings["-"] != "" {
+			continue
+		}
+		_, notNull := settings["NOT NULL"]
+		_, primaryKey := settings["PRIMARYKEY"]
+		for _, ident := range f.Names {
+			columns = append(columns, modelColumn{
+				Name:    column,
+				Field:   ident.Name,
+				GoType:  types.ExprString(f.Type),
+				DBType:  settings["TYPE"],
+				NotNull: notNull || primaryKey,
+			})
+		}
+	}
+	return columns
+}
+
+// gormSettings 解析 gorm 标签，键转换为大写，与 gorm/schema.ParseTagSetting 一致
+func gormSettings(tag string) map[string]string {
+	settings := make(map[string]string)
+	for _, part := range strings.Split(tag, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(part, ":")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if key == "-" {
+			value = "-"
+		}
+		settings[key] = value
+	}
+	return settings
+}
+
+// stringLiteral 返回字符串字面量的值
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// sortedTableNames 返回排序后的表名
+func sortedTableNames(tables map[string]modelTable) []string {
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// defaultJobs 默认同时处理的数据库数量
+const defaultJobs = 4
+
+// runParallel 用最多 jobs 个 goroutine 对 0..n-1 执行 fn，返回每个下标的错误
+// fn 中的 panic（如 gorm/gen 生成模型失败）转换为错误，不影响其它数据库
+func runParallel(n, jobs int, fn func(i int) error) []error {
+	if jobs < 1 {
+		jobs = 1
+	}
+	errs := make([]error, n)
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < jobs && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = callRecover(func() error { return fn(i) })
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return errs
+}
+
+// callRecover 执行 fn，把 panic 转换为错误
+func callRecover(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return fn()
+}
+
+// closeDB 关闭 gorm 底层的连接池
+func closeDB(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
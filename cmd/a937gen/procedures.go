@@ -0,0 +1,1374 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/imports"
+
+	"gorm.io/gorm"
+)
+
+// 存储过程包装方法写入数据库 OutPath 下的文件
+const (
+	proceduresFile     = "procedures.gen.go"
+	proceduresFakeFile = "procedures_fake.gen.go"
+)
+
+// ProcedureInfo 存储过程（或存储函数）信息
+type ProcedureInfo struct {
+	Name       string           `json:"name" yaml:"name"`
+	Type       string           `json:"type" yaml:"type"` // PROCEDURE / FUNCTION
+	Parameters []ProcedureParam `json:"parameters" yaml:"parameters"`
+	ReturnType string           `json:"return_type" yaml:"return_type"` // 存储函数 RETURNS 的完整类型，如 decimal(10,2)
+	Definition string           `json:"definition" yaml:"definition"`
+	ResultSets []ResultSet      `json:"result_sets" yaml:"result_sets"` // 结果集，未声明也未探测时为空
+
+	returnDecimal bool // 存储函数的返回值使用 types.Decimal，由 applyDecimalTypes 设置
+}
+
+// ProcedureParam 存储过程参数，来自 information_schema.PARAMETERS
+type ProcedureParam struct {
+	Ordinal    int    `json:"ordinal" yaml:"ordinal"`
+	Mode       string `json:"mode" yaml:"mode"` // IN / OUT / INOUT
+	Name       string `json:"name" yaml:"name"`
+	DataType   string `json:"data_type" yaml:"data_type"`     // 基础类型，如 int、varchar
+	ColumnType string `json:"column_type" yaml:"column_type"` // 完整类型，如 int(11) unsigned
+	Precision  int    `json:"precision" yaml:"precision"`
+	Scale      int    `json:"scale" yaml:"scale"`
+	Unsigned   bool   `json:"unsigned" yaml:"unsigned"`
+
+	decimal bool // 使用 types.Decimal，由 applyDecimalTypes 设置
+}
+
+// ResultColumn 存储过程结果集中的一列
+type ResultColumn struct {
+	Name string `yaml:"name" json:"name"`
+	Type string `yaml:"type" json:"type"` // MySQL 类型，如 int(11) unsigned、varchar(32)
+
+	decimal bool // 使用 types.Decimal，由 applyDecimalTypes 设置
+}
+
+// ResultSet 存储过程返回的一个结果集
+type ResultSet struct {
+	Name    string         `yaml:"name" json:"name"` // 结果集名称，用作生成的字段名，缺省为 Set<序号>
+	Columns []ResultColumn `yaml:"columns" json:"columns"`
+}
+
+// runProcedures 生成存储过程包装方法
+func runProcedures(args []string) error {
+	fs := newFlagSet("procedures", "procedures [参数]")
+	var cf configFlags
+	cf.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	config, databases, err := cf.load()
+	if err != nil {
+		return err
+	}
+
+	// 生成所有数据库的存储过程包装方法
+	failed := failures{}
+	for _, dbConfig := range databases {
+		fmt.Printf("\n正在生成数据库 %s 的存储过程包装方法...\n", dbConfig.Name)
+		fmt.Printf("配置信息: Name=%s, OutPath=%s\n", dbConfig.Name, dbConfig.OutPath)
+		err := generateProcedures(dbConfig, config.Global)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "生成数据库 %s 的存储过程失败: %v\n", dbConfig.Name, err)
+			failed.add(dbConfig.Name, err)
+			continue
+		}
+		fmt.Printf("数据库 %s 的存储过程包装方法生成完成！\n", dbConfig.Name)
+	}
+	if err := failed.err("生成存储过程"); err != nil {
+		return err
+	}
+
+	fmt.Println("\n所有数据库存储过程包装方法生成完成！")
+	return nil
+}
+
+// generateProcedures 生成指定数据库的存储过程包装方法，global 中的 decimal 配置与模型共用
+func generateProcedures(dbConfig DatabaseConfig, global GlobalConfig) error {
+	// 连接数据库或打开表结构快照
+	db, err := openDatabase(&dbConfig)
+	if err != nil {
+		return err
+	}
+	defer closeDB(db)
+	printSnapshotSource(dbConfig, db)
+
+	schema, err := dbConfig.schemaName()
+	if err != nil {
+		return err
+	}
+
+	// 创建输出目录
+	fmt.Printf("创建输出目录: %s\n", dbConfig.OutPath)
+	err = os.MkdirAll(dbConfig.OutPath, 0755)
+	if err != nil {
+		return fmt.Errorf("创建输出目录失败 (路径: %s): %v", dbConfig.OutPath, err)
+	}
+	fmt.Printf("输出目录创建成功\n")
+
+	// 获取存储过程列表
+	var procedures []ProcedureInfo
+	if len(dbConfig.Procedures) > 0 {
+		// 使用指定的存储过程
+		fmt.Printf("使用指定的存储过程: %v\n", dbConfig.Procedures)
+		count, err := countRoutines(db, schema)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("schema %s 中没有任何存储过程或函数，但配置中指定了 %d 个存储过程", schema, len(dbConfig.Procedures))
+		}
+		for _, procName := range dbConfig.Procedures {
+			proc, err := getProcedureInfo(db, schema, procName)
+			if err != nil {
+				fmt.Printf("警告: 无法获取存储过程 %s 的信息: %v\n", procName, err)
+				continue
+			}
+			procedures = append(procedures, *proc)
+		}
+	} else {
+		// 获取所有存储过程
+		fmt.Printf("正在扫描 schema %s 的存储过程...\n", schema)
+		allProcedures, err := getAllProcedures(db, schema)
+		if err != nil {
+			return fmt.Errorf("获取存储过程列表失败: %v", err)
+		}
+		procedures = allProcedures
+		for _, proc := range procedures {
+			if proc.isFunction() {
+				fmt.Printf("找到存储函数: %s (参数: %s, 返回: %s)\n", proc.Name, describeParameters(proc.Parameters), proc.ReturnType)
+			} else {
+				fmt.Printf("找到存储过程: %s (参数: %s)\n", proc.Name, describeParameters(proc.Parameters))
+			}
+		}
+		fmt.Printf("数据库 %s 扫描完成，找到 %d 个存储过程\n", dbConfig.Name, len(procedures))
+	}
+
+	if len(procedures) == 0 {
+		fmt.Printf("数据库 %s 中没有找到存储过程\n", dbConfig.Name)
+		return nil
+	}
+
+	// 确定结果集列
+	for i := range procedures {
+		err := resolveResultSets(db, dbConfig, &procedures[i])
+		if err != nil {
+			return fmt.Errorf("获取存储过程 %s 的结果列失败: %v", procedures[i].Name, err)
+		}
+	}
+	decimal := boolValue(global.optionsFor(dbConfig).Decimal)
+	decimalColumns := global.decimalColumns(dbConfig)
+	for i := range procedures {
+		applyDecimalTypes(&procedures[i], decimal, decimalColumns)
+	}
+
+	// 生成存储过程包装方法文件
+	err = generateProcedureFile(dbConfig, procedures)
+	if err != nil {
+		return fmt.Errorf("生成存储过程文件失败: %v", err)
+	}
+
+	fmt.Printf("生成的文件位于: %s/procedures.gen.go\n", dbConfig.OutPath)
+	return nil
+}
+
+// countRoutines 统计 schema 中存储过程和存储函数的数量
+func countRoutines(db *gorm.DB, dbName string) (int64, error) {
+	if snapshot := snapshotOf(db); snapshot != nil {
+		return int64(len(snapshot.Routines)), nil
+	}
+
+	var count int64
+	countQuery := `
+		SELECT COUNT(*) as count
+		FROM information_schema.ROUTINES
+		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE IN ('PROCEDURE', 'FUNCTION')
+	`
+
+	err := db.Raw(countQuery, dbName).Row().Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("查询存储过程数量失败: %v", err)
+	}
+	return count, nil
+}
+
+// getAllProcedures 获取所有存储过程和存储函数
+func getAllProcedures(db *gorm.DB, dbName string) ([]ProcedureInfo, error) {
+	if snapshot := snapshotOf(db); snapshot != nil {
+		return append([]ProcedureInfo(nil), snapshot.Routines...), nil
+	}
+
+	var procedures []ProcedureInfo
+
+	// 首先检查是否有存储过程
+	count, err := countRoutines(db, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	if count == 0 {
+		return procedures, nil
+	}
+
+	// 查询存储过程信息
+	query := `
+		SELECT
+			ROUTINE_NAME as name,
+			ROUTINE_TYPE as type,
+			COALESCE(DTD_IDENTIFIER, '') as return_type,
+			COALESCE(ROUTINE_DEFINITION, '') as definition
+		FROM information_schema.ROUTINES
+		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_TYPE IN ('PROCEDURE', 'FUNCTION')
+		ORDER BY ROUTINE_TYPE DESC, ROUTINE_NAME
+	`
+
+	rows, err := db.Raw(query, dbName).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("查询存储过程信息失败: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var proc ProcedureInfo
+		var definition sql.NullString
+
+		err := rows.Scan(&proc.Name, &proc.Type, &proc.ReturnType, &definition)
+		if err != nil {
+			fmt.Printf("警告: 扫描存储过程 %s 失败: %v\n", proc.Name, err)
+			continue
+		}
+
+		if definition.Valid {
+			proc.Definition = definition.String
+		}
+		procedures = append(procedures, proc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取存储过程信息失败: %v", err)
+	}
+
+	// 查询参数信息
+	for i := range procedures {
+		params, err := getProcedureParameters(db, dbName, procedures[i].Name, procedures[i].Type)
+		if err != nil {
+			return nil, fmt.Errorf("获取存储过程 %s 的参数失败: %v", procedures[i].Name, err)
+		}
+		procedures[i].Parameters = params
+	}
+
+	return procedures, nil
+}
+
+// getProcedureInfo 获取指定存储过程或存储函数的信息
+func getProcedureInfo(db *gorm.DB, dbName, procName string) (*ProcedureInfo, error) {
+	if snapshot := snapshotOf(db); snapshot != nil {
+		// 存储过程名不区分大小写
+		for _, proc := range snapshot.Routines {
+			if strings.EqualFold(proc.Name, procName) {
+				return &proc, nil
+			}
+		}
+		return nil, sql.ErrNoRows
+	}
+
+	var proc ProcedureInfo
+	query := `
+		SELECT
+			ROUTINE_NAME as name,
+			ROUTINE_TYPE as type,
+			COALESCE(DTD_IDENTIFIER, '') as return_type,
+			ROUTINE_DEFINITION as definition
+		FROM information_schema.ROUTINES
+		WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = ? AND ROUTINE_TYPE IN ('PROCEDURE', 'FUNCTION')
+	`
+
+	var definition sql.NullString
+	err := db.Raw(query, dbName, procName).Row().Scan(&proc.Name, &proc.Type, &proc.ReturnType, &definition)
+	if err != nil {
+		return nil, err
+	}
+	proc.Definition = definition.String
+
+	// 查询参数信息
+	proc.Parameters, err = getProcedureParameters(db, dbName, proc.Name, proc.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return &proc, nil
+}
+
+// getProcedureParameters 从 information_schema.PARAMETERS 读取存储过程参数
+// ROUTINE_DEFINITION 只包含 BEGIN...END 过程体，无法从中得到参数列表；
+// 存储函数 ORDINAL_POSITION 为 0 的记录是返回值，这里不读取
+func getProcedureParameters(db *gorm.DB, dbName, procName, routineType string) ([]ProcedureParam, error) {
+	query := `
+		SELECT
+			ORDINAL_POSITION,
+			COALESCE(PARAMETER_MODE, 'IN'),
+			COALESCE(PARAMETER_NAME, ''),
+			DATA_TYPE,
+			DTD_IDENTIFIER,
+			COALESCE(NUMERIC_PRECISION, 0),
+			COALESCE(NUMERIC_SCALE, 0)
+		FROM information_schema.PARAMETERS
+		WHERE SPECIFIC_SCHEMA = ? AND SPECIFIC_NAME = ? AND ROUTINE_TYPE = ? AND ORDINAL_POSITION > 0
+		ORDER BY ORDINAL_POSITION
+	`
+
+	rows, err := db.Raw(query, dbName, procName, routineType).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("查询存储过程参数失败: %v", err)
+	}
+	defer rows.Close()
+
+	var params []ProcedureParam
+	for rows.Next() {
+		var param ProcedureParam
+		err := rows.Scan(&param.Ordinal, &param.Mode, &param.Name, &param.DataType,
+			&param.ColumnType, &param.Precision, &param.Scale)
+		if err != nil {
+			return nil, err
+		}
+		param.Mode = strings.ToUpper(param.Mode)
+		param.DataType = strings.ToLower(param.DataType)
+		param.Unsigned = strings.Contains(strings.ToLower(param.ColumnType), "unsigned")
+		params = append(params, param)
+	}
+
+	return params, rows.Err()
+}
+
+// describeParameters 参数列表的可读描述
+func describeParameters(params []ProcedureParam) string {
+	if len(params) == 0 {
+		return "无"
+	}
+	parts := make([]string, len(params))
+	for i, param := range params {
+		parts[i] = fmt.Sprintf("%s %s %s", param.Mode, param.Name, param.ColumnType)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// goType 将 MySQL 参数类型映射为 Go 类型
+func (p ProcedureParam) goType() string {
+	return mysqlGoType(p.DataType, p.Unsigned, p.decimal)
+}
+
+// mysqlGoType 将 MySQL 基础类型映射为 Go 类型，decimal 为 true 时定点和浮点类型映射为 types.Decimal
+func mysqlGoType(dataType string, unsigned, decimal bool) string {
+	switch dataType {
+	case "tinyint":
+		if unsigned {
+			return "uint8"
+		}
+		return "int8"
+	case "smallint", "year":
+		if unsigned {
+			return "uint16"
+		}
+		return "int16"
+	case "mediumint", "int", "integer":
+		if unsigned {
+			return "uint32"
+		}
+		return "int32"
+	case "bigint":
+		if unsigned {
+			return "uint64"
+		}
+		return "int64"
+	case "bit":
+		return "uint64"
+	case "float", "double", "real", "decimal", "numeric":
+		switch {
+		case decimal:
+			return "types.Decimal"
+		case dataType == "float":
+			return "float32"
+		default:
+			// 与 gorm/gen 生成的模型字段一致
+			return "float64"
+		}
+	case "date", "datetime", "timestamp":
+		return "time.Time"
+	case "binary", "varbinary", "tinyblob", "blob", "mediumblob", "longblob":
+		return "[]byte"
+	default:
+		// char、varchar、text、enum、set、json、time 等
+		return "string"
+	}
+}
+
+// goType 将结果列的 MySQL 类型映射为 Go 类型
+func (c ResultColumn) goType() string {
+	dataType, unsigned := mysqlBaseType(c.Type)
+	return mysqlGoType(dataType, unsigned, c.decimal)
+}
+
+// mysqlBaseType 从完整类型中取出基础类型和是否无符号
+// 驱动探测到的类型形如 "UNSIGNED INT"，声明的类型形如 "int(11) unsigned"
+func mysqlBaseType(columnType string) (string, bool) {
+	columnType = strings.ToLower(strings.TrimSpace(columnType))
+	unsigned := strings.Contains(columnType, "unsigned")
+	columnType = strings.TrimSpace(strings.Replace(columnType, "unsigned", "", 1))
+	if i := strings.IndexAny(columnType, "( "); i >= 0 {
+		columnType = columnType[:i]
+	}
+	return columnType, unsigned
+}
+
+// applyDecimalTypes 按 decimal 和 decimal_columns 配置确定哪些参数、结果列和返回值使用 types.Decimal，与模型字段的类型一致
+// decimal 为 true 时所有 DECIMAL 使用 types.Decimal；decimal_columns 模式中的表名匹配存储过程名，如 "p_commission.amount"，
+// 存储函数的返回值按列名 return 匹配，如 "f_balance.return"。与模型相同，只转换 DECIMAL 和浮点类型
+func applyDecimalTypes(proc *ProcedureInfo, decimal bool, patterns []string) {
+	useDecimal := func(name, columnType string) bool {
+		dataType, _ := mysqlBaseType(columnType)
+		if decimal && (dataType == "decimal" || dataType == "numeric") {
+			return true
+		}
+		if !matchAnyColumn(patterns, proc.Name, name) {
+			return false
+		}
+		switch dataType {
+		case "float", "double", "real", "decimal", "numeric":
+			return true
+		}
+		fmt.Printf("警告: %s.%s 的类型 %s 不能作为 Decimal\n", proc.Name, name, columnType)
+		return false
+	}
+
+	for i, param := range proc.Parameters {
+		proc.Parameters[i].decimal = useDecimal(param.Name, param.ColumnType)
+	}
+	for _, set := range proc.ResultSets {
+		for j, column := range set.Columns {
+			set.Columns[j].decimal = useDecimal(column.Name, column.Type)
+		}
+	}
+	if proc.isFunction() {
+		proc.returnDecimal = useDecimal("return", proc.ReturnType)
+	}
+}
+
+// resolveResultSets 从配置声明或试调用中确定存储过程的结果集
+func resolveResultSets(db *gorm.DB, dbConfig DatabaseConfig, proc *ProcedureInfo) error {
+	if proc.isFunction() {
+		// 存储函数只返回单个值
+		return nil
+	}
+	resultConfig := dbConfig.Results[proc.Name]
+	if len(resultConfig.ResultSets) > 0 {
+		proc.ResultSets = resultConfig.ResultSets
+		return nil
+	}
+	if len(resultConfig.Columns) > 0 {
+		proc.ResultSets = []ResultSet{{Columns: resultConfig.Columns}}
+		return nil
+	}
+	if !resultConfig.Probe && !dbConfig.ProbeResults {
+		return nil
+	}
+	if snapshotOf(db) != nil {
+		fmt.Printf("警告: 使用表结构快照时不能试调用存储过程 %s，请在 results 中声明结果列\n", proc.Name)
+		return nil
+	}
+
+	sets, err := probeResultSets(db, *proc, resultConfig.Args)
+	if err != nil {
+		return err
+	}
+	proc.ResultSets = sets
+	for i, set := range sets {
+		fmt.Printf("探测到存储过程 %s 第 %d 个结果集的列: %v\n", proc.Name, i+1, set.Columns)
+	}
+	return nil
+}
+
+// errProbeRollback 用于回滚试调用事务
+var errProbeRollback = errors.New("probe rollback")
+
+// probeResultSets 在回滚的事务中试调用存储过程，依次读取每个结果集的列信息
+func probeResultSets(db *gorm.DB, proc ProcedureInfo, probeArgs []string) ([]ResultSet, error) {
+	callSQL, callArgs := callStatement(proc)
+	args := make([]interface{}, len(callArgs))
+	if len(probeArgs) > 0 {
+		if len(probeArgs) != len(callArgs) {
+			return nil, fmt.Errorf("试调用参数数量为 %d，存储过程需要 %d 个 IN 参数", len(probeArgs), len(callArgs))
+		}
+		for i, arg := range probeArgs {
+			args[i] = arg
+		}
+	}
+
+	var sets []ResultSet
+	err := db.Transaction(func(tx *gorm.DB) error {
+		rows, err := tx.Raw(callSQL, args...).Rows()
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for {
+			columnTypes, err := rows.ColumnTypes()
+			if err != nil {
+				return err
+			}
+			// CALL 最后返回的状态结果没有列
+			if len(columnTypes) > 0 {
+				var set ResultSet
+				for _, columnType := range columnTypes {
+					set.Columns = append(set.Columns, ResultColumn{
+						Name: columnType.Name(),
+						Type: strings.ToLower(columnType.DatabaseTypeName()),
+					})
+				}
+				sets = append(sets, set)
+			}
+			// NextResultSet 会丢弃当前结果集中未读取的行
+			if !rows.NextResultSet() {
+				break
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return errProbeRollback
+	})
+	if err != nil && !errors.Is(err, errProbeRollback) {
+		return nil, err
+	}
+	return sets, nil
+}
+
+// setName 返回第 i 个结果集在生成代码中的名称
+func (proc ProcedureInfo) setName(i int) string {
+	if name := toCamelCase(proc.ResultSets[i].Name); name != "" {
+		return name
+	}
+	return fmt.Sprintf("Set%d", i+1)
+}
+
+// rowType 返回第 i 个结果集的行结构体名
+func (proc ProcedureInfo) rowType(i int) string {
+	if len(proc.ResultSets) == 1 {
+		return toCamelCase(proc.Name) + "Row"
+	}
+	return toCamelCase(proc.Name) + proc.setName(i) + "Row"
+}
+
+// resultFieldNames 为结果列生成唯一的 Go 字段名
+func resultFieldNames(columns []ResultColumn) []string {
+	names := make([]string, len(columns))
+	used := make(map[string]bool)
+	for i, column := range columns {
+		var b strings.Builder
+		for _, r := range column.Name {
+			if r == '_' || (r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))) {
+				b.WriteRune(r)
+			} else {
+				b.WriteRune('_')
+			}
+		}
+		name := toCamelCase(strings.Trim(b.String(), "_"))
+		if name == "" || unicode.IsDigit(rune(name[0])) {
+			name = "Col" + name
+		}
+		if used[name] {
+			name += strconv.Itoa(i + 1)
+		}
+		used[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// reservedParamNames 生成的包装方法中已使用的名称：接收者、ctx、方法体中的局部变量、session 闭包的参数和引用的包名
+// 同名的参数会重复声明或遮蔽这些名称，需要加 Arg 后缀
+var reservedParamNames = map[string]bool{
+	"pc": true, "f": true, "ctx": true, "tx": true,
+	"db": true, "rows": true, "err": true, "out": true, "result": true, "results": true, "zero": true,
+	"gorm": true, "types": true,
+}
+
+// goName 生成参数在 Go 代码中的变量名
+func (p ProcedureParam) goName() string {
+	name := toCamelCase(strings.Trim(p.Name, "_"))
+	if name == "" {
+		return fmt.Sprintf("arg%d", p.Ordinal)
+	}
+	name = strings.ToLower(name[:1]) + name[1:]
+	if token.IsKeyword(name) || reservedParamNames[name] {
+		name += "Arg"
+	}
+	return name
+}
+
+// generateProcedureFile 生成存储过程包装方法文件
+func generateProcedureFile(dbConfig DatabaseConfig, procedures []ProcedureInfo) error {
+	var code strings.Builder
+
+	// 生成包声明和导入
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n")
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n")
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n\n")
+
+	// 与 gorm/gen 生成的查询代码使用同一个包名
+	packageName := dbConfig.packageName()
+	code.WriteString(fmt.Sprintf("package %s\n\n", packageName))
+
+	code.WriteString("import (\n")
+	code.WriteString("\t\"context\"\n")
+	code.WriteString("\t\"database/sql\"\n")
+	if hasMultipleResultSets(procedures) {
+		code.WriteString("\t\"fmt\"\n")
+	}
+	if proceduresUseType(procedures, "time.Time") {
+		code.WriteString("\t\"time\"\n")
+	}
+	code.WriteString("\n")
+	if proceduresUseType(procedures, "types.Decimal") {
+		code.WriteString(fmt.Sprintf("\t%q\n", typesPkgPath))
+	}
+	code.WriteString("\t\"gorm.io/gorm\"\n")
+	code.WriteString(")\n\n")
+
+	// 生成存储过程包装结构体
+	code.WriteString("// ProcedureCaller 存储过程调用器\n")
+	code.WriteString("type ProcedureCaller struct {\n")
+	code.WriteString("\tdb *gorm.DB\n")
+	code.WriteString("}\n\n")
+
+	// 生成构造函数
+	code.WriteString("// NewProcedureCaller 创建存储过程调用器\n")
+	code.WriteString("func NewProcedureCaller(db *gorm.DB) *ProcedureCaller {\n")
+	code.WriteString("\treturn &ProcedureCaller{db: db}\n")
+	code.WriteString("}\n\n")
+
+	// 生成便捷构造函数（使用数据库名称）
+	constructorName := fmt.Sprintf("New%sProcedureCaller", strings.Title(packageName))
+	code.WriteString(fmt.Sprintf("// %s 创建 %s 数据库的存储过程调用器\n", constructorName, dbConfig.Name))
+	code.WriteString(fmt.Sprintf("func %s(db *gorm.DB) *ProcedureCaller {\n", constructorName))
+	code.WriteString("\treturn &ProcedureCaller{db: db}\n")
+	code.WriteString("}\n\n")
+
+	// 生成覆盖所有包装方法的接口，便于在测试中替换
+	interfaceName := dbConfig.interfaceName()
+	methods := wrapperMethodsOf(procedures)
+	code.WriteString(fmt.Sprintf("// %s %s 数据库存储过程调用接口，*ProcedureCaller 和 Fake%s 均实现该接口\n", interfaceName, dbConfig.Name, interfaceName))
+	code.WriteString(fmt.Sprintf("type %s interface {\n", interfaceName))
+	for _, method := range methods {
+		code.WriteString(fmt.Sprintf("\t%s\n", method.signature()))
+	}
+	code.WriteString(fmt.Sprintf("\tTransaction(fc func(tx %s) error, opts ...*sql.TxOptions) error\n", interfaceName))
+	code.WriteString(fmt.Sprintf("\tWithContext(ctx context.Context) %s\n", interfaceName))
+	code.WriteString("}\n\n")
+	code.WriteString(fmt.Sprintf("var _ %s = (*ProcedureCaller)(nil)\n\n", interfaceName))
+
+	generateSessionHelpers(&code)
+	if hasMultipleResultSets(procedures) {
+		generateNextResultSet(&code)
+	}
+
+	// 生成每个存储过程的包装方法
+	for _, proc := range procedures {
+		generateProcedureMethod(&code, proc)
+	}
+
+	// 生成事务支持的方法
+	code.WriteString("// Transaction 执行事务中的存储过程\n")
+	code.WriteString(fmt.Sprintf("func (pc *ProcedureCaller) Transaction(fc func(tx %s) error, opts ...*sql.TxOptions) error {\n", interfaceName))
+	code.WriteString("\treturn pc.db.Transaction(func(tx *gorm.DB) error {\n")
+	code.WriteString("\t\treturn fc(NewProcedureCaller(tx))\n")
+	code.WriteString("\t}, opts...)\n")
+	code.WriteString("}\n\n")
+
+	// 生成上下文支持的方法
+	code.WriteString("// WithContext 设置上下文\n")
+	code.WriteString(fmt.Sprintf("func (pc *ProcedureCaller) WithContext(ctx context.Context) %s {\n", interfaceName))
+	code.WriteString("\treturn &ProcedureCaller{db: pc.db.WithContext(ctx)}\n")
+	code.WriteString("}\n")
+
+	// 格式化并写入文件
+	err := writeGoFile(filepath.Join(dbConfig.OutPath, proceduresFile), code.String())
+	if err != nil {
+		return err
+	}
+
+	return generateFakeFile(dbConfig, procedures)
+}
+
+// writeGoFile 格式化并写入生成的 Go 代码
+func writeGoFile(filePath, code string) error {
+	source, err := format.Source([]byte(code))
+	if err != nil {
+		return fmt.Errorf("格式化生成代码失败 (%s): %v", filePath, err)
+	}
+	return os.WriteFile(filePath, source, 0644)
+}
+
+// writeGoFileImports 格式化并写入生成的 Go 代码，补全标准库导入并删除未使用的导入
+// 用于字段类型来自配置、无法预先确定导入的代码
+func writeGoFileImports(filePath, code string) error {
+	source, err := imports.Process(filePath, []byte(code), nil)
+	if err != nil {
+		return fmt.Errorf("格式化生成代码失败 (%s): %v", filePath, err)
+	}
+	return os.WriteFile(filePath, source, 0644)
+}
+
+// wrapperMethod 生成的一个包装方法的签名信息
+type wrapperMethod struct {
+	Name      string
+	Procedure string
+	Params    []ProcedureParam // 调用方传入的参数
+	Results   []string         // 返回值类型，最后一个总是 error
+}
+
+// signature 返回方法签名，不含接收者
+func (m wrapperMethod) signature() string {
+	params := []string{"ctx context.Context"}
+	for _, param := range m.Params {
+		params = append(params, fmt.Sprintf("%s %s", param.goName(), param.goType()))
+	}
+	results := strings.Join(m.Results, ", ")
+	if len(m.Results) > 1 {
+		results = "(" + results + ")"
+	}
+	return fmt.Sprintf("%s(%s) %s", m.Name, strings.Join(params, ", "), results)
+}
+
+// wrapperMethodsOf 返回所有存储过程生成的包装方法，与 generateProcedureMethod 生成的方法一一对应
+func wrapperMethodsOf(procedures []ProcedureInfo) []wrapperMethod {
+	var methods []wrapperMethod
+	for _, proc := range procedures {
+		methodName := toCamelCase(proc.Name)
+		var inputs []ProcedureParam
+		for _, param := range proc.Parameters {
+			if param.isInput() {
+				inputs = append(inputs, param)
+			}
+		}
+
+		switch {
+		case proc.isFunction():
+			methods = append(methods, wrapperMethod{methodName, proc.Name, inputs, []string{proc.returnGoType(), "error"}})
+		case len(proc.outputs()) > 0:
+			outStruct := "*" + methodName + "Out"
+			methods = append(methods,
+				wrapperMethod{methodName, proc.Name, inputs, []string{outStruct, "error"}},
+				wrapperMethod{methodName + "WithResult", proc.Name, inputs, []string{resultType(proc), outStruct, "error"}})
+		default:
+			methods = append(methods,
+				wrapperMethod{methodName, proc.Name, inputs, []string{"error"}},
+				wrapperMethod{methodName + "WithResult", proc.Name, inputs, []string{resultType(proc), "error"}})
+		}
+	}
+	return methods
+}
+
+// zeroValue 返回 Fake 在未注册处理函数时的默认返回值
+// 指针返回空结构体，避免调用方直接解引用时 panic
+func zeroValue(goType string) string {
+	switch {
+	case goType == "error":
+		return "nil"
+	case goType == "string":
+		return `""`
+	case goType == "time.Time", goType == "types.Decimal":
+		return goType + "{}"
+	case strings.HasPrefix(goType, "*"):
+		return "&" + goType[1:] + "{}"
+	case strings.HasPrefix(goType, "[]"), strings.HasPrefix(goType, "map["):
+		return "nil"
+	default:
+		return "0"
+	}
+}
+
+// generateFakeFile 生成内存中的接口实现，测试可以为每个方法注册处理函数并检查调用记录
+func generateFakeFile(dbConfig DatabaseConfig, procedures []ProcedureInfo) error {
+	var code strings.Builder
+	interfaceName := dbConfig.interfaceName()
+	fakeName := "Fake" + interfaceName
+	methods := wrapperMethodsOf(procedures)
+
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n")
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n")
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n\n")
+	code.WriteString(fmt.Sprintf("package %s\n\n", dbConfig.packageName()))
+
+	code.WriteString("import (\n")
+	code.WriteString("\t\"context\"\n")
+	code.WriteString("\t\"database/sql\"\n")
+	code.WriteString("\t\"sync\"\n")
+	// 结果行结构体中的类型不出现在 Fake 中，只看方法签名
+	if methodsUseType(methods, "time.Time") {
+		code.WriteString("\t\"time\"\n")
+	}
+	if methodsUseType(methods, "types.Decimal") {
+		code.WriteString(fmt.Sprintf("\n\t%q\n", typesPkgPath))
+	}
+	code.WriteString(")\n\n")
+
+	code.WriteString("// ProcedureCall 一次存储过程调用的记录\n")
+	code.WriteString("type ProcedureCall struct {\n")
+	code.WriteString("\tMethod    string        // 调用的包装方法名\n")
+	code.WriteString("\tProcedure string        // 存储过程名\n")
+	code.WriteString("\tArgs      []interface{} // 调用参数，不含 ctx\n")
+	code.WriteString("}\n\n")
+
+	code.WriteString(fmt.Sprintf("// %s 内存中的 %s 实现，用于测试\n", fakeName, interfaceName))
+	code.WriteString("// 为 <方法名>Func 字段赋值即可注册处理函数，未注册的方法返回零值\n")
+	code.WriteString(fmt.Sprintf("type %s struct {\n", fakeName))
+	for _, method := range methods {
+		code.WriteString(fmt.Sprintf("\t%sFunc func%s\n", method.Name, strings.TrimPrefix(method.signature(), method.Name)))
+	}
+	code.WriteString("\n\tmu    sync.Mutex\n")
+	code.WriteString("\tcalls []ProcedureCall\n")
+	code.WriteString("}\n\n")
+	code.WriteString(fmt.Sprintf("var _ %s = (*%s)(nil)\n\n", interfaceName, fakeName))
+
+	code.WriteString(fmt.Sprintf("// New%s 创建 %s\n", fakeName, fakeName))
+	code.WriteString(fmt.Sprintf("func New%s() *%s {\n", fakeName, fakeName))
+	code.WriteString(fmt.Sprintf("\treturn &%s{}\n", fakeName))
+	code.WriteString("}\n\n")
+
+	code.WriteString("// record 记录一次调用\n")
+	code.WriteString(fmt.Sprintf("func (f *%s) record(method, procedure string, args ...interface{}) {\n", fakeName))
+	code.WriteString("\tf.mu.Lock()\n")
+	code.WriteString("\tdefer f.mu.Unlock()\n")
+	code.WriteString("\tf.calls = append(f.calls, ProcedureCall{Method: method, Procedure: procedure, Args: args})\n")
+	code.WriteString("}\n\n")
+
+	code.WriteString("// Calls 返回所有调用记录\n")
+	code.WriteString(fmt.Sprintf("func (f *%s) Calls() []ProcedureCall {\n", fakeName))
+	code.WriteString("\tf.mu.Lock()\n")
+	code.WriteString("\tdefer f.mu.Unlock()\n")
+	code.WriteString("\treturn append([]ProcedureCall(nil), f.calls...)\n")
+	code.WriteString("}\n\n")
+
+	code.WriteString("// CallsTo 返回指定存储过程的调用记录\n")
+	code.WriteString(fmt.Sprintf("func (f *%s) CallsTo(procedure string) []ProcedureCall {\n", fakeName))
+	code.WriteString("\tvar calls []ProcedureCall\n")
+	code.WriteString("\tfor _, call := range f.Calls() {\n")
+	code.WriteString("\t\tif call.Procedure == procedure {\n")
+	code.WriteString("\t\t\tcalls = append(calls, call)\n")
+	code.WriteString("\t\t}\n")
+	code.WriteString("\t}\n")
+	code.WriteString("\treturn calls\n")
+	code.WriteString("}\n\n")
+
+	code.WriteString("// Reset 清空调用记录\n")
+	code.WriteString(fmt.Sprintf("func (f *%s) Reset() {\n", fakeName))
+	code.WriteString("\tf.mu.Lock()\n")
+	code.WriteString("\tdefer f.mu.Unlock()\n")
+	code.WriteString("\tf.calls = nil\n")
+	code.WriteString("}\n\n")
+
+	for _, method := range methods {
+		args := make([]string, len(method.Params))
+		for i, param := range method.Params {
+			args[i] = param.goName()
+		}
+		zeros := make([]string, len(method.Results))
+		for i, result := range method.Results {
+			zeros[i] = zeroValue(result)
+		}
+
+		code.WriteString(fmt.Sprintf("// %s 记录调用并执行注册的处理函数\n", method.Name))
+		code.WriteString(fmt.Sprintf("func (f *%s) %s {\n", fakeName, method.signature()))
+		code.WriteString(fmt.Sprintf("\tf.record(%q, %q%s)\n", method.Name, method.Procedure, joinArgs(args)))
+		code.WriteString(fmt.Sprintf("\tif f.%sFunc != nil {\n", method.Name))
+		code.WriteString(fmt.Sprintf("\t\treturn f.%sFunc(ctx%s)\n", method.Name, joinArgs(args)))
+		code.WriteString("\t}\n")
+		code.WriteString(fmt.Sprintf("\treturn %s\n", strings.Join(zeros, ", ")))
+		code.WriteString("}\n\n")
+	}
+
+	code.WriteString("// Transaction 直接在 Fake 上执行 fc\n")
+	code.WriteString(fmt.Sprintf("func (f *%s) Transaction(fc func(tx %s) error, opts ...*sql.TxOptions) error {\n", fakeName, interfaceName))
+	code.WriteString("\treturn fc(f)\n")
+	code.WriteString("}\n\n")
+
+	code.WriteString("// WithContext 返回 Fake 本身\n")
+	code.WriteString(fmt.Sprintf("func (f *%s) WithContext(ctx context.Context) %s {\n", fakeName, interfaceName))
+	code.WriteString("\treturn f\n")
+	code.WriteString("}\n")
+
+	return writeGoFile(filepath.Join(dbConfig.OutPath, proceduresFakeFile), code.String())
+}
+
+// methodsUseType 判断包装方法的签名中是否用到 goType
+func methodsUseType(methods []wrapperMethod, goType string) bool {
+	for _, method := range methods {
+		if strings.Contains(method.signature(), goType) {
+			return true
+		}
+	}
+	return false
+}
+
+// proceduresUseType 判断生成代码中是否用到 goType，用于确定导入的包
+func proceduresUseType(procedures []ProcedureInfo, goType string) bool {
+	for _, proc := range procedures {
+		if proc.isFunction() && proc.returnGoType() == goType {
+			return true
+		}
+		for _, param := range proc.Parameters {
+			if param.goType() == goType {
+				return true
+			}
+		}
+		for _, set := range proc.ResultSets {
+			for _, column := range set.Columns {
+				if column.goType() == goType {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isInput 参数是否需要调用方传入 (IN/INOUT)
+func (p ProcedureParam) isInput() bool {
+	return p.Mode == "IN" || p.Mode == "INOUT"
+}
+
+// isOutput 参数是否会由存储过程写回 (OUT/INOUT)
+func (p ProcedureParam) isOutput() bool {
+	return p.Mode == "OUT" || p.Mode == "INOUT"
+}
+
+// sessionVar OUT/INOUT 参数绑定的会话变量名
+func (p ProcedureParam) sessionVar() string {
+	return "@_" + p.goName()
+}
+
+// isFunction 是否为存储函数
+func (proc ProcedureInfo) isFunction() bool {
+	return proc.Type == "FUNCTION"
+}
+
+// returnGoType 存储函数返回值对应的 Go 类型
+func (proc ProcedureInfo) returnGoType() string {
+	return ResultColumn{Type: proc.ReturnType, decimal: proc.returnDecimal}.goType()
+}
+
+// outputs 返回存储过程的 OUT/INOUT 参数
+func (proc ProcedureInfo) outputs() []ProcedureParam {
+	var params []ProcedureParam
+	for _, param := range proc.Parameters {
+		if param.isOutput() {
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// callStatement 生成 CALL 语句及其绑定参数
+// IN 参数使用占位符绑定，OUT/INOUT 参数必须绑定到会话变量
+func callStatement(proc ProcedureInfo) (string, []string) {
+	placeholders := make([]string, len(proc.Parameters))
+	var args []string
+	for i, param := range proc.Parameters {
+		if param.Mode == "IN" {
+			placeholders[i] = "?"
+			args = append(args, param.goName())
+		} else {
+			placeholders[i] = param.sessionVar()
+		}
+	}
+	return fmt.Sprintf("CALL %s(%s)", proc.Name, strings.Join(placeholders, ", ")), args
+}
+
+// setStatement 生成为 INOUT 参数赋初值的 SET 语句，没有 INOUT 参数时返回空字符串
+func setStatement(proc ProcedureInfo) (string, []string) {
+	var assigns, args []string
+	for _, param := range proc.Parameters {
+		if param.Mode == "INOUT" {
+			assigns = append(assigns, param.sessionVar()+" = ?")
+			args = append(args, param.goName())
+		}
+	}
+	if len(assigns) == 0 {
+		return "", nil
+	}
+	return "SET " + strings.Join(assigns, ", "), args
+}
+
+// selectOutputStatement 生成读取 OUT/INOUT 会话变量的 SELECT 语句
+func selectOutputStatement(proc ProcedureInfo) string {
+	var columns []string
+	for _, param := range proc.outputs() {
+		columns = append(columns, fmt.Sprintf("%s AS %s", param.sessionVar(), param.goName()))
+	}
+	return "SELECT " + strings.Join(columns, ", ")
+}
+
+// joinArgs 拼接调用参数，非空时带前导逗号
+func joinArgs(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return ", " + strings.Join(args, ", ")
+}
+
+// generateSessionHelpers 生成同一连接执行和结果扫描的辅助方法
+func generateSessionHelpers(code *strings.Builder) {
+	code.WriteString("// session 在同一个数据库连接上执行 fc，保证 OUT 参数的会话变量可见\n")
+	code.WriteString("func (pc *ProcedureCaller) session(ctx context.Context, fc func(tx *gorm.DB) error) error {\n")
+	code.WriteString("\tdb := pc.db.WithContext(ctx)\n")
+	code.WriteString("\tif _, ok := db.Statement.ConnPool.(gorm.TxCommitter); ok {\n")
+	code.WriteString("\t\t// 事务内已经固定在单个连接上\n")
+	code.WriteString("\t\treturn fc(db)\n")
+	code.WriteString("\t}\n")
+	code.WriteString("\treturn db.Connection(fc)\n")
+	code.WriteString("}\n\n")
+
+	code.WriteString("// scanRowMaps 将结果集逐行读取为 map\n")
+	code.WriteString("func scanRowMaps(rows *sql.Rows) ([]map[string]interface{}, error) {\n")
+	code.WriteString("\tvar results []map[string]interface{}\n")
+	code.WriteString("\tcolumns, err := rows.Columns()\n")
+	code.WriteString("\tif err != nil {\n")
+	code.WriteString("\t\treturn nil, err\n")
+	code.WriteString("\t}\n\n")
+	code.WriteString("\tfor rows.Next() {\n")
+	code.WriteString("\t\tvalues := make([]interface{}, len(columns))\n")
+	code.WriteString("\t\tscanArgs := make([]interface{}, len(values))\n")
+	code.WriteString("\t\tfor i := range values {\n")
+	code.WriteString("\t\t\tscanArgs[i] = &values[i]\n")
+	code.WriteString("\t\t}\n\n")
+	code.WriteString("\t\terr = rows.Scan(scanArgs...)\n")
+	code.WriteString("\t\tif err != nil {\n")
+	code.WriteString("\t\t\treturn nil, err\n")
+	code.WriteString("\t\t}\n\n")
+	code.WriteString("\t\trow := make(map[string]interface{})\n")
+	code.WriteString("\t\tfor i, col := range columns {\n")
+	code.WriteString("\t\t\trow[col] = values[i]\n")
+	code.WriteString("\t\t}\n")
+	code.WriteString("\t\tresults = append(results, row)\n")
+	code.WriteString("\t}\n\n")
+	code.WriteString("\treturn results, rows.Err()\n")
+	code.WriteString("}\n\n")
+}
+
+// generateRowStruct 生成存储过程结果集的行结构体
+func generateRowStruct(code *strings.Builder, proc ProcedureInfo, i int) {
+	set := proc.ResultSets[i]
+	structName := proc.rowType(i)
+	if len(proc.ResultSets) == 1 {
+		code.WriteString(fmt.Sprintf("// %s 存储过程 %s 的结果行\n", structName, proc.Name))
+	} else {
+		code.WriteString(fmt.Sprintf("// %s 存储过程 %s 第 %d 个结果集的行\n", structName, proc.Name, i+1))
+	}
+	code.WriteString(fmt.Sprintf("type %s struct {\n", structName))
+	for j, name := range resultFieldNames(set.Columns) {
+		column := set.Columns[j]
+		code.WriteString(fmt.Sprintf("\t%s %s `gorm:\"column:%s\" json:\"%s\"` // %s\n",
+			name, column.goType(), column.Name, column.Name, column.Type))
+	}
+	code.WriteString("}\n\n")
+}
+
+// generateResultTypes 生成存储过程结果集的行结构体及读取函数
+func generateResultTypes(code *strings.Builder, proc ProcedureInfo) {
+	if len(proc.ResultSets) == 0 {
+		return
+	}
+	for i := range proc.ResultSets {
+		generateRowStruct(code, proc, i)
+	}
+
+	methodName := toCamelCase(proc.Name)
+	scanFunc := "scan" + methodName + "Result"
+	writeLoop := func(i int, target string) {
+		code.WriteString("\tfor rows.Next() {\n")
+		code.WriteString(fmt.Sprintf("\t\tvar row %s\n", proc.rowType(i)))
+		code.WriteString("\t\tif err := db.ScanRows(rows, &row); err != nil {\n")
+		code.WriteString("\t\t\treturn nil, err\n")
+		code.WriteString("\t\t}\n")
+		code.WriteString(fmt.Sprintf("\t\t%s = append(%s, row)\n", target, target))
+		code.WriteString("\t}\n")
+	}
+
+	if len(proc.ResultSets) == 1 {
+		code.WriteString(fmt.Sprintf("// %s 读取存储过程 %s 的结果集\n", scanFunc, proc.Name))
+		code.WriteString(fmt.Sprintf("func %s(db *gorm.DB, rows *sql.Rows) (%s, error) {\n", scanFunc, resultType(proc)))
+		code.WriteString(fmt.Sprintf("\tvar results %s\n", resultType(proc)))
+		writeLoop(0, "results")
+		code.WriteString("\treturn results, rows.Err()\n")
+		code.WriteString("}\n\n")
+		return
+	}
+
+	resultStruct := methodName + "Result"
+	code.WriteString(fmt.Sprintf("// %s 存储过程 %s 返回的 %d 个结果集\n", resultStruct, proc.Name, len(proc.ResultSets)))
+	code.WriteString(fmt.Sprintf("type %s struct {\n", resultStruct))
+	for i := range proc.ResultSets {
+		code.WriteString(fmt.Sprintf("\t%s []%s `json:\"%s\"`\n", proc.setName(i), proc.rowType(i), toSnakeCase(proc.setName(i))))
+	}
+	code.WriteString("}\n\n")
+
+	code.WriteString(fmt.Sprintf("// %s 依次读取存储过程 %s 的各个结果集\n", scanFunc, proc.Name))
+	code.WriteString(fmt.Sprintf("func %s(db *gorm.DB, rows *sql.Rows) (*%s, error) {\n", scanFunc, resultStruct))
+	code.WriteString(fmt.Sprintf("\tvar result %s\n", resultStruct))
+	for i := range proc.ResultSets {
+		if i > 0 {
+			code.WriteString(fmt.Sprintf("\tif err := nextResultSet(rows, %q, %d); err != nil {\n", proc.Name, i+1))
+			code.WriteString("\t\treturn nil, err\n")
+			code.WriteString("\t}\n")
+		}
+		writeLoop(i, "result."+proc.setName(i))
+	}
+	code.WriteString("\tif err := rows.Err(); err != nil {\n")
+	code.WriteString("\t\treturn nil, err\n")
+	code.WriteString("\t}\n")
+	code.WriteString("\treturn &result, nil\n")
+	code.WriteString("}\n\n")
+}
+
+// generateNextResultSet 生成切换结果集的辅助函数
+func generateNextResultSet(code *strings.Builder) {
+	code.WriteString("// nextResultSet 切换到存储过程的第 index 个结果集\n")
+	code.WriteString("func nextResultSet(rows *sql.Rows, procedure string, index int) error {\n")
+	code.WriteString("\tif err := rows.Err(); err != nil {\n")
+	code.WriteString("\t\treturn err\n")
+	code.WriteString("\t}\n")
+	code.WriteString("\tif !rows.NextResultSet() {\n")
+	code.WriteString("\t\tif err := rows.Err(); err != nil {\n")
+	code.WriteString("\t\t\treturn err\n")
+	code.WriteString("\t\t}\n")
+	code.WriteString("\t\treturn fmt.Errorf(\"存储过程 %s 没有返回第 %d 个结果集\", procedure, index)\n")
+	code.WriteString("\t}\n")
+	code.WriteString("\treturn nil\n")
+	code.WriteString("}\n\n")
+}
+
+// hasMultipleResultSets 判断是否有存储过程声明了多个结果集
+func hasMultipleResultSets(procedures []ProcedureInfo) bool {
+	for _, proc := range procedures {
+		if len(proc.ResultSets) > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// writeScanRows 生成将 rows 读取到 results 变量的代码
+func writeScanRows(code *strings.Builder, indent, db string, proc ProcedureInfo) {
+	if len(proc.ResultSets) == 0 {
+		code.WriteString(fmt.Sprintf("%sresults, err = scanRowMaps(rows)\n", indent))
+		return
+	}
+	code.WriteString(fmt.Sprintf("%sresults, err = scan%sResult(%s, rows)\n", indent, toCamelCase(proc.Name), db))
+}
+
+// resultType 返回 *WithResult 方法的结果类型
+func resultType(proc ProcedureInfo) string {
+	switch len(proc.ResultSets) {
+	case 0:
+		return "[]map[string]interface{}"
+	case 1:
+		return "[]" + proc.rowType(0)
+	default:
+		return "*" + toCamelCase(proc.Name) + "Result"
+	}
+}
+
+// generateOutputStruct 生成存储过程 OUT/INOUT 参数的结果结构体
+func generateOutputStruct(code *strings.Builder, proc ProcedureInfo, structName string) {
+	code.WriteString(fmt.Sprintf("// %s 存储过程 %s 的 OUT/INOUT 参数\n", structName, proc.Name))
+	code.WriteString(fmt.Sprintf("type %s struct {\n", structName))
+	for _, param := range proc.outputs() {
+		name := param.goName()
+		code.WriteString(fmt.Sprintf("\t%s %s `gorm:\"column:%s\" json:\"%s\"` // %s %s\n",
+			strings.ToUpper(name[:1])+name[1:], param.goType(), name, param.Name, param.Mode, param.ColumnType))
+	}
+	code.WriteString("}\n\n")
+}
+
+// generateProcedureMethod 生成单个存储过程的包装方法
+func generateProcedureMethod(code *strings.Builder, proc ProcedureInfo) {
+	methodName := toCamelCase(proc.Name)
+
+	// 生成方法签名，参数按 ORDINAL_POSITION 排列
+	var params []string
+	for _, param := range proc.Parameters {
+		if param.isInput() {
+			params = append(params, fmt.Sprintf("%s %s", param.goName(), param.goType()))
+		}
+	}
+
+	paramStr := strings.Join(params, ", ")
+	if paramStr != "" {
+		paramStr = ", " + paramStr
+	}
+
+	if proc.isFunction() {
+		generateFunctionMethod(code, proc, methodName, paramStr)
+		return
+	}
+
+	generateResultTypes(code, proc)
+
+	callSQL, callArgs := callStatement(proc)
+	if len(proc.outputs()) > 0 {
+		generateOutputMethods(code, proc, methodName, paramStr, callSQL, callArgs)
+		return
+	}
+
+	// 生成方法注释
+	code.WriteString(fmt.Sprintf("// %s 调用存储过程 %s\n", methodName, proc.Name))
+	code.WriteString(fmt.Sprintf("func (pc *ProcedureCaller) %s(ctx context.Context%s) error {\n", methodName, paramStr))
+
+	// 生成方法体
+	code.WriteString(fmt.Sprintf("\treturn pc.db.WithContext(ctx).Exec(%q%s).Error\n", callSQL, joinArgs(callArgs)))
+
+	code.WriteString("}\n\n")
+
+	// 生成返回结果的版本
+	resultMethodName := methodName + "WithResult"
+	code.WriteString(fmt.Sprintf("// %s 调用存储过程 %s 并返回结果\n", resultMethodName, proc.Name))
+	code.WriteString(fmt.Sprintf("func (pc *ProcedureCaller) %s(ctx context.Context%s) (%s, error) {\n", resultMethodName, paramStr, resultType(proc)))
+	code.WriteString("\tdb := pc.db.WithContext(ctx)\n")
+	code.WriteString(fmt.Sprintf("\trows, err := db.Raw(%q%s).Rows()\n", callSQL, joinArgs(callArgs)))
+	code.WriteString("\tif err != nil {\n")
+	code.WriteString("\t\treturn nil, err\n")
+	code.WriteString("\t}\n")
+	code.WriteString("\tdefer rows.Close()\n\n")
+	code.WriteString(fmt.Sprintf("\tvar results %s\n", resultType(proc)))
+	writeScanRows(code, "\t", "db", proc)
+	code.WriteString("\tif err != nil {\n")
+	code.WriteString("\t\treturn nil, err\n")
+	code.WriteString("\t}\n")
+	code.WriteString("\treturn results, nil\n")
+	code.WriteString("}\n\n")
+}
+
+// generateFunctionMethod 生成存储函数的包装方法，通过 SELECT fn(?, ...) 读取返回值
+// 返回 NULL 时得到返回类型的零值
+func generateFunctionMethod(code *strings.Builder, proc ProcedureInfo, methodName, paramStr string) {
+	placeholders := make([]string, len(proc.Parameters))
+	args := make([]string, len(proc.Parameters))
+	for i, param := range proc.Parameters {
+		placeholders[i] = "?"
+		args[i] = param.goName()
+	}
+	selectSQL := fmt.Sprintf("SELECT %s(%s)", proc.Name, strings.Join(placeholders, ", "))
+	returnType := proc.returnGoType()
+
+	code.WriteString(fmt.Sprintf("// %s 调用存储函数 %s，返回 %s\n", methodName, proc.Name, proc.ReturnType))
+	code.WriteString(fmt.Sprintf("func (pc *ProcedureCaller) %s(ctx context.Context%s) (%s, error) {\n", methodName, paramStr, returnType))
+	code.WriteString(fmt.Sprintf("\tvar result *%s\n", returnType))
+	code.WriteString(fmt.Sprintf("\terr := pc.db.WithContext(ctx).Raw(%q%s).Row().Scan(&result)\n", selectSQL, joinArgs(args)))
+	code.WriteString("\tif err != nil || result == nil {\n")
+	code.WriteString(fmt.Sprintf("\t\tvar zero %s\n", returnType))
+	code.WriteString("\t\treturn zero, err\n")
+	code.WriteString("\t}\n")
+	code.WriteString("\treturn *result, nil\n")
+	code.WriteString("}\n\n")
+}
+
+// generateOutputMethods 生成带 OUT/INOUT 参数的存储过程包装方法
+// CALL 与读取会话变量的 SELECT 必须在同一个连接上执行
+func generateOutputMethods(code *strings.Builder, proc ProcedureInfo, methodName, paramStr, callSQL string, callArgs []string) {
+	outStruct := methodName + "Out"
+	generateOutputStruct(code, proc, outStruct)
+
+	setSQL, setArgs := setStatement(proc)
+	selectSQL := selectOutputStatement(proc)
+
+	writeSet := func(indent string) {
+		if setSQL == "" {
+			return
+		}
+		code.WriteString(fmt.Sprintf("%sif err := tx.Exec(%q%s).Error; err != nil {\n", indent, setSQL, joinArgs(setArgs)))
+		code.WriteString(indent + "\treturn err\n")
+		code.WriteString(indent + "}\n")
+	}
+
+	code.WriteString(fmt.Sprintf("// %s 调用存储过程 %s 并返回 OUT/INOUT 参数\n", methodName, proc.Name))
+	code.WriteString(fmt.Sprintf("func (pc *ProcedureCaller) %s(ctx context.Context%s) (*%s, error) {\n", methodName, paramStr, outStruct))
+	code.WriteString(fmt.Sprintf("\tvar out %s\n", outStruct))
+	code.WriteString("\terr := pc.session(ctx, func(tx *gorm.DB) error {\n")
+	writeSet("\t\t")
+	code.WriteString(fmt.Sprintf("\t\tif err := tx.Exec(%q%s).Error; err != nil {\n", callSQL, joinArgs(callArgs)))
+	code.WriteString("\t\t\treturn err\n")
+	code.WriteString("\t\t}\n")
+	code.WriteString(fmt.Sprintf("\t\treturn tx.Raw(%q).Scan(&out).Error\n", selectSQL))
+	code.WriteString("\t})\n")
+	code.WriteString("\tif err != nil {\n")
+	code.WriteString("\t\treturn nil, err\n")
+	code.WriteString("\t}\n")
+	code.WriteString("\treturn &out, nil\n")
+	code.WriteString("}\n\n")
+
+	resultMethodName := methodName + "WithResult"
+	code.WriteString(fmt.Sprintf("// %s 调用存储过程 %s 并返回结果和 OUT/INOUT 参数\n", resultMethodName, proc.Name))
+	code.WriteString(fmt.Sprintf("func (pc *ProcedureCaller) %s(ctx context.Context%s) (%s, *%s, error) {\n", resultMethodName, paramStr, resultType(proc), outStruct))
+	code.WriteString(fmt.Sprintf("\tvar results %s\n", resultType(proc)))
+	code.WriteString(fmt.Sprintf("\tvar out %s\n", outStruct))
+	code.WriteString("\terr := pc.session(ctx, func(tx *gorm.DB) error {\n")
+	writeSet("\t\t")
+	code.WriteString(fmt.Sprintf("\t\trows, err := tx.Raw(%q%s).Rows()\n", callSQL, joinArgs(callArgs)))
+	code.WriteString("\t\tif err != nil {\n")
+	code.WriteString("\t\t\treturn err\n")
+	code.WriteString("\t\t}\n")
+	writeScanRows(code, "\t\t", "tx", proc)
+	code.WriteString("\t\t// 关闭结果集后才能在同一连接上读取会话变量\n")
+	code.WriteString("\t\trows.Close()\n")
+	code.WriteString("\t\tif err != nil {\n")
+	code.WriteString("\t\t\treturn err\n")
+	code.WriteString("\t\t}\n")
+	code.WriteString(fmt.Sprintf("\t\treturn tx.Raw(%q).Scan(&out).Error\n", selectSQL))
+	code.WriteString("\t})\n")
+	code.WriteString("\tif err != nil {\n")
+	code.WriteString("\t\treturn nil, nil, err\n")
+	code.WriteString("\t}\n")
+	code.WriteString("\treturn results, &out, nil\n")
+	code.WriteString("}\n\n")
+}
+
+// toCamelCase 转换为驼峰命名
+func toCamelCase(str string) string {
+	parts := strings.Split(str, "_")
+	for i := range parts {
+		if len(parts[i]) > 0 {
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// toSnakeCase 将驼峰命名转换为下划线命名
+func toSnakeCase(str string) string {
+	var b strings.Builder
+	for i, r := range str {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package main
+
+import "testing"
+
+func TestApplyDecimalTypes(t *testing.T) {
+	newProc := func() ProcedureInfo {
+		return ProcedureInfo{
+			Name:       "f_balance",
+			Type:       "FUNCTION",
+			ReturnType: "decimal(12,2)",
+			Parameters: []ProcedureParam{
+				{Name: "p_amount", DataType: "decimal", ColumnType: "decimal(12,2)"},
+				{Name: "p_ratio", DataType: "double", ColumnType: "double"},
+				{Name: "p_userid", DataType: "int", ColumnType: "int(11)"},
+			},
+			ResultSets: []ResultSet{{Columns: []ResultColumn{
+				{Name: "score", Type: "decimal(10,2) unsigned"},
+				{Name: "rate", Type: "float"},
+				{Name: "name", Type: "varchar(32)"},
+			}}},
+		}
+	}
+	decimals := func(proc ProcedureInfo) []bool {
+		result := []bool{proc.returnDecimal}
+		for _, param := range proc.Parameters {
+			result = append(result, param.decimal)
+		}
+		for _, column := range proc.ResultSets[0].Columns {
+			result = append(result, column.decimal)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		decimal  bool
+		patterns []string
+		want     []bool // 返回值、p_amount、p_ratio、p_userid、score、rate、name
+	}{
+		{"关闭", false, nil, []bool{false, false, false, false, false, false, false}},
+		{"decimal", true, nil, []bool{true, true, false, false, true, false, false}},
+		{"浮点列", false, []string{"p_ratio", "f_balance.rate"}, []bool{false, false, true, false, false, true, false}},
+		{"返回值", false, []string{"f_balance.return"}, []bool{true, false, false, false, false, false, false}},
+		{"其它存储过程", false, []string{"f_other.return", "f_other.p_amount"}, []bool{false, false, false, false, false, false, false}},
+		{"非数字类型", false, []string{"p_userid", "name"}, []bool{false, false, false, false, false, false, false}},
+	}
+	for _, tt := range tests {
+		proc := newProc()
+		applyDecimalTypes(&proc, tt.decimal, tt.patterns)
+		got := decimals(proc)
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: applyDecimalTypes = %v, want %v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+	"gorm.io/gen"
+	"gorm.io/gen/field"
+	"gorm.io/gorm"
+)
+
+// RelationConfig 表之间的关联，From 表中的外键列引用 To 表中的列
+// From 模型生成 belongs-to 关联字段，配置 Reverse 时 To 模型生成反向的 has-many/has-one 字段
+type RelationConfig struct {
+	From        string `yaml:"from"`         // 外键列，表名.列名，如 t_games.room_uuid
+	To          string `yaml:"to"`           // 被引用的列，表名.列名，如 t_rooms.uuid
+	Field       string `yaml:"field"`        // From 模型中的关联字段名，默认按外键列名生成，如 Room；"-" 表示不生成
+	Reverse     string `yaml:"reverse"`      // To 模型中反向关联的字段名，如 Games，为空时不生成
+	ReverseType string `yaml:"reverse_type"` // 反向关联类型，has_many（默认）或 has_one
+}
+
+// reverseTypes 反向关联类型
+var reverseTypes = map[string]field.RelationshipType{
+	"":         field.HasMany,
+	"has_many": field.HasMany,
+	"has_one":  field.HasOne,
+}
+
+// validate 检查关联配置
+func (c RelationConfig) validate() error {
+	if err := validColumnKey(c.From); err != nil {
+		return fmt.Errorf("关联的 from: %v", err)
+	}
+	if err := validColumnKey(c.To); err != nil {
+		return fmt.Errorf("关联的 to: %v", err)
+	}
+	if _, ok := reverseTypes[c.ReverseType]; !ok {
+		return fmt.Errorf("关联 %s 的 reverse_type %q 只能是 has_many 或 has_one", c.From, c.ReverseType)
+	}
+	return nil
+}
+
+// relation 解析后的关联
+type relation struct {
+	FromTable, FromColumn string
+	ToTable, ToColumn     string
+	Field                 string // From 模型中的 belongs-to 字段名，为空时不生成
+	Reverse               string // To 模型中的反向字段名，为空时不生成
+	ReverseType           field.RelationshipType
+	Discovered            bool // 从外键约束中发现
+}
+
+// gormTag 返回关联字段的 gorm 标签，外键和引用都使用模型字段名
+func (r relation) gormTag() field.GormTag {
+	return field.GormTag{}.
+		Set("foreignKey", fieldNaming.SchemaName(r.FromColumn)).
+		Set("references", fieldNaming.SchemaName(r.ToColumn))
+}
+
+// defaultRelationField 按外键列名生成 belongs-to 字段名，去掉与被引用列相同的后缀，
+// 如 room_uuid -> t_rooms.uuid 为 Room，userid -> newuseraccounts.id 为 User；
+// 去掉后为空时（如 matchId -> matchlog.matchId）使用被引用的模型名
+func defaultRelationField(fromColumn, toColumn, toStruct string) string {
+	name := strings.ToLower(fromColumn)
+	name = strings.TrimSuffix(name, strings.ToLower(toColumn))
+	name = strings.TrimRight(name, "_")
+	if name == "" {
+		return toStruct
+	}
+	return fieldNaming.SchemaName(name)
+}
+
+// resolveRelations 合并配置的关联和从外键约束发现的关联，去掉涉及未生成的表的关联
+// 同一外键列同时出现在两者中时以配置为准
+func resolveRelations(db *gorm.DB, dbConfig DatabaseConfig, discover bool, tables []string) ([]relation, error) {
+	tableSet := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		tableSet[table] = true
+	}
+	structName := db.Config.NamingStrategy.SchemaName
+
+	var relations []relation
+	configured := make(map[string]bool)
+	for _, c := range dbConfig.Relations {
+		fromTable, fromColumn := splitColumnKey(c.From)
+		toTable, toColumn := splitColumnKey(c.To)
+		if !tableSet[fromTable] || !tableSet[toTable] {
+			fmt.Printf("警告: 关联 %s -> %s 涉及的表不在生成列表中，已忽略\n", c.From, c.To)
+			continue
+		}
+		r := relation{
+			FromTable: fromTable, FromColumn: fromColumn,
+			ToTable: toTable, ToColumn: toColumn,
+			Field:       c.Field,
+			Reverse:     c.Reverse,
+			ReverseType: reverseTypes[c.ReverseType],
+		}
+		switch r.Field {
+		case "":
+			r.Field = defaultRelationField(fromColumn, toColumn, structName(toTable))
+		case "-":
+			r.Field = ""
+		}
+		configured[strings.ToLower(c.From)] = true
+		relations = append(relations, r)
+	}
+
+	if !discover {
+		return relations, nil
+	}
+	schema, err := dbConfig.schemaName()
+	if err != nil {
+		return nil, err
+	}
+	foreignKeys, err := foreignKeyRelations(db, schema)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range foreignKeys {
+		if configured[strings.ToLower(r.FromTable+"."+r.FromColumn)] || !tableSet[r.FromTable] || !tableSet[r.ToTable] {
+			continue
+		}
+		r.Field = defaultRelationField(r.FromColumn, r.ToColumn, structName(r.ToTable))
+		r.Reverse = inflection.Plural(structName(r.FromTable))
+		r.ReverseType = field.HasMany
+		relations = append(relations, r)
+	}
+	return relations, nil
+}
+
+// foreignKeyRelations 从 information_schema.KEY_COLUMN_USAGE 读取单列外键约束，使用快照时从快照读取
+func foreignKeyRelations(db *gorm.DB, schema string) ([]relation, error) {
+	constraints := make(map[string][]relation)
+	var names []string
+	add := func(constraint string, r relation) {
+		key := r.FromTable + "." + constraint
+		if constraints[key] == nil {
+			names = append(names, key)
+		}
+		constraints[key] = append(constraints[key], r)
+	}
+
+	if snapshot := snapshotOf(db); snapshot != nil {
+		for _, table := range snapshot.Tables {
+			for _, fk := range table.ForeignKeys {
+				for i, column := range fk.Columns {
+					add(fk.Name, relation{Discovered: true, FromTable: table.Name, FromColumn: column, ToTable: fk.RefTable, ToColumn: fk.RefColumns[i]})
+				}
+			}
+		}
+	} else if err := queryForeignKeys(db, schema, add); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+	var relations []relation
+	for _, name := range names {
+		if len(constraints[name]) > 1 {
+			fmt.Printf("警告: 外键 %s 包含多列，不生成关联\n", name)
+			continue
+		}
+		relations = append(relations, constraints[name][0])
+	}
+	return relations, nil
+}
+
+// queryForeignKeys 查询引用同一 schema 中的表的外键约束，按约束的列顺序逐列回调
+func queryForeignKeys(db *gorm.DB, schema string, add func(constraint string, r relation)) error {
+	query := `
+		SELECT CONSTRAINT_NAME, TABLE_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+		FROM information_schema.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = ? AND REFERENCED_TABLE_SCHEMA = ? AND REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY TABLE_NAME, CONSTRAINT_NAME, ORDINAL_POSITION
+	`
+
+	rows, err := db.Raw(query, schema, schema).Rows()
+	if err != nil {
+		return fmt.Errorf("查询外键约束失败: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var constraint string
+		r := relation{Discovered: true}
+		if err := rows.Scan(&constraint, &r.FromTable, &r.FromColumn, &r.ToTable, &r.ToColumn); err != nil {
+			return fmt.Errorf("读取外键约束失败: %v", err)
+		}
+		add(constraint, r)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("读取外键约束失败: %v", err)
+	}
+	return nil
+}
+
+// relatedTables 返回关联涉及的表
+func relatedTables(relations []relation) []string {
+	seen := make(map[string]bool)
+	var tables []string
+	for _, r := range relations {
+		for _, table := range []string{r.FromTable, r.ToTable} {
+			if !seen[table] {
+				seen[table] = true
+				tables = append(tables, table)
+			}
+		}
+	}
+	return tables
+}
+
+// relateFunc 返回关联到某个表的模型选项，包装 gen.FieldRelate，因为 gorm/gen 没有导出模型元数据的类型
+type relateFunc func(relationship field.RelationshipType, fieldName string, config *field.RelateConfig) gen.ModelOpt
+
+// relationOpts 返回表的关联字段选项
+// relate 中的关联表模型不带关联字段，避免互相关联的两个表无限嵌套；fields 为表中已有的字段名，用于检查重名
+func relationOpts(table string, relations []relation, relate map[string]relateFunc, fields map[string]bool) []gen.ModelOpt {
+	var opts []gen.ModelOpt
+	add := func(name string, relationship field.RelationshipType, target string, r relation) {
+		if fields[name] {
+			fmt.Printf("警告: %s 已有字段 %s，关联 %s.%s -> %s.%s 未生成，请在 relations 中指定 field 或 reverse\n",
+				table, name, r.FromTable, r.FromColumn, r.ToTable, r.ToColumn)
+			return
+		}
+		fields[name] = true
+		// belongs-to、has-one 使用指针，否则自引用或 has_one 反向关联的模型是递归类型
+		config := &field.RelateConfig{
+			RelatePointer: relationship == field.BelongsTo || relationship == field.HasOne,
+			GORMTag:       r.gormTag(),
+		}
+		opts = append(opts, relate[target](relationship, name, config))
+	}
+
+	for _, r := range relations {
+		if r.FromTable == table && r.Field != "" {
+			add(r.Field, field.BelongsTo, r.ToTable, r)
+		}
+		if r.ToTable == table && r.Reverse != "" {
+			add(r.Reverse, r.ReverseType, r.FromTable, r)
+		}
+	}
+	return opts
+}
@@ -0,0 +1,483 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// DatabaseInfo 数据库信息
+type DatabaseInfo struct {
+	Name       string          `json:"name" yaml:"name"`
+	Tables     []string        `json:"tables" yaml:"tables"`
+	Procedures []ProcedureInfo `json:"procedures" yaml:"procedures"`
+}
+
+// scanResult json、yaml 格式输出的扫描结果
+type scanResult struct {
+	Server    string         `json:"server" yaml:"server"`
+	Databases []DatabaseInfo `json:"databases" yaml:"databases"`
+}
+
+// scanFormats scan -format 可用的输出格式
+var scanFormats = []string{"table", "json", "yaml"}
+
+// runScan 扫描 MySQL 服务器上的数据库，scan drift 检查模型与表结构是否一致
+func runScan(args []string) error {
+	if len(args) > 0 && args[0] == "drift" {
+		return runDrift(args[1:])
+	}
+
+	fs := newFlagSet("scan", "scan [参数] | scan drift [参数]")
+	var server scanServer
+	fs.StringVar(&server.host, "host", getEnvOrDefault("DB_HOST", "127.0.0.1"), "MySQL 主机，默认取环境变量 DB_HOST")
+	fs.StringVar(&server.port, "port", getEnvOrDefault("DB_PORT", "3306"), "MySQL 端口，默认取环境变量 DB_PORT")
+	fs.StringVar(&server.user, "user", getEnvOrDefault("DB_USER", "root"), "MySQL 用户名，默认取环境变量 DB_USER")
+	fs.StringVar(&server.password, "password", getEnvOrDefault("DB_PASSWORD", ""), "MySQL 密码，默认取环境变量 DB_PASSWORD")
+	snapshotDir := fs.String("snapshot", "", "把每个数据库的表结构快照导出到目录中的 <数据库名>.json，供 models/procedures -snapshot 使用")
+	format := fs.String("format", "table", "输出格式: "+strings.Join(scanFormats, "、")+"，json 和 yaml 输出完整的表和存储过程信息")
+	write := fs.String("write", "", "把扫描到的数据库写入配置文件（如 databases.yml），文件已存在时只追加其中没有的数据库")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	valid := false
+	for _, f := range scanFormats {
+		valid = valid || f == *format
+	}
+	if !valid {
+		fmt.Fprintf(fs.Output(), "不支持的输出格式 %q，可用: %s\n", *format, strings.Join(scanFormats, "、"))
+		return errUsage
+	}
+	return scan(server, *snapshotDir, *format, *write)
+}
+
+// scanServer 扫描的 MySQL 服务器
+type scanServer struct {
+	host, port, user, password string
+}
+
+// addr 返回 host:port
+func (s scanServer) addr() string {
+	return s.host + ":" + s.port
+}
+
+// dsn 返回连接服务器上数据库 dbName 的 DSN，dbName 为空时不指定数据库
+func (s scanServer) dsn(dbName string) string {
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		s.user, s.password, s.host, s.port, dbName)
+}
+
+// scan 扫描数据库并按 format 输出结果，snapshotDir 非空时同时导出表结构快照，configFile 非空时写入配置文件
+// 进度和警告输出到 stderr，stdout 只有扫描结果
+func scan(server scanServer, snapshotDir, format, configFile string) error {
+	// 扫描数据库
+	failed := failures{}
+	databases, err := scanDatabases(server, snapshotDir, failed)
+	if err != nil {
+		return fmt.Errorf("扫描数据库失败: %v", err)
+	}
+
+	// 输出结果
+	result := scanResult{Server: server.addr(), Databases: databases}
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("序列化结果失败: %v", err)
+		}
+		fmt.Println(string(data))
+	case "yaml":
+		data, err := yaml.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("序列化结果失败: %v", err)
+		}
+		fmt.Print(string(data))
+	default:
+		if err := printScan(result, server, configFile == ""); err != nil {
+			return err
+		}
+	}
+
+	if configFile != "" {
+		if err := writeScanConfig(configFile, server, databases); err != nil {
+			return err
+		}
+	}
+	return failed.err("扫描")
+}
+
+// printScan 以文本形式输出扫描结果，printConfig 为 true 时附带环境变量命令和 databases.yml 配置
+func printScan(result scanResult, server scanServer, printConfig bool) error {
+	fmt.Printf("连接到 MySQL 服务器: %s\n", result.Server)
+	fmt.Printf("找到 %d 个数据库:\n\n", len(result.Databases))
+
+	for i, db := range result.Databases {
+		fmt.Printf("%d. 数据库: %s\n", i+1, db.Name)
+
+		if len(db.Tables) > 0 {
+			fmt.Printf("   表数量: %d\n", len(db.Tables))
+			fmt.Printf("   表名: %s\n", strings.Join(db.Tables, ", "))
+		} else {
+			fmt.Printf("   表数量: 0 (空数据库)\n")
+		}
+
+		fmt.Printf("   存储过程数量: %d\n", len(db.Procedures))
+		for _, proc := range db.Procedures {
+			if proc.Type == "FUNCTION" {
+				fmt.Printf("   存储函数: %s", proc.Name)
+			} else {
+				fmt.Printf("   存储过程: %s", proc.Name)
+			}
+			if len(proc.Parameters) > 0 {
+				fmt.Printf(" (参数: %s)", describeParameters(proc.Parameters))
+			}
+			fmt.Println()
+		}
+		fmt.Println()
+	}
+	if !printConfig {
+		return nil
+	}
+
+	// 生成环境变量命令
+	fmt.Println("生成环境变量命令:")
+	fmt.Println("```bash")
+	for _, db := range result.Databases {
+		if len(db.Tables) > 0 {
+			fmt.Printf("export DB_DSN_%s=%s\n", strings.ToUpper(db.Name), shellQuote(server.dsn(db.Name)))
+		}
+	}
+	fmt.Println("```")
+
+	// 生成配置文件
+	var entries []interface{}
+	for _, db := range result.Databases {
+		if len(db.Tables) > 0 {
+			entries = append(entries, scanConfigEntry(server, db.Name))
+		}
+	}
+	data, err := yaml.Marshal(yaml.MapSlice{{Key: "databases", Value: entries}, {Key: "global", Value: scanGlobalConfig()}})
+	if err != nil {
+		return fmt.Errorf("序列化配置失败: %v", err)
+	}
+	fmt.Println("\n生成 databases.yml 配置:")
+	fmt.Println("```yaml")
+	fmt.Print(string(data))
+	fmt.Println("```")
+	return nil
+}
+
+// shellQuote 用单引号引用 shell 参数
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// scanConfigEntry 返回扫描到的数据库的配置项，tables 为空表示生成所有表
+func scanConfigEntry(server scanServer, dbName string) yaml.MapSlice {
+	return yaml.MapSlice{
+		{Key: "name", Value: strings.ToUpper(dbName)},
+		{Key: "dsn", Value: server.dsn(dbName)},
+		{Key: "out_path", Value: "./models/" + dbName},
+		{Key: "tables", Value: []string{}},
+	}
+}
+
+// scanGlobalConfig 新建配置文件时使用的 global 配置
+func scanGlobalConfig() yaml.MapSlice {
+	return yaml.MapSlice{
+		{Key: "mode", Value: defaultMode},
+		{Key: "field_with_index_tag", Value: true},
+		{Key: "field_with_type_tag", Value: true},
+		{Key: "field_signable", Value: true},
+		{Key: "field_nullable", Value: true},
+	}
+}
+
+// writeScanConfig 把扫描到的有表的数据库写入配置文件
+// 文件已存在时只在 databases 列表末尾追加新的数据库（按 schema 或名称匹配的数据库不修改），
+// 其余内容和注释原样保留；没有新数据库时不改写文件
+func writeScanConfig(filename string, server scanServer, databases []DatabaseInfo) error {
+	existing := make(map[string]bool) // 已配置的 schema 和大写的名称
+	data, err := ioutil.ReadFile(filename)
+	switch {
+	case err == nil:
+		config, err := loadConfig(filename)
+		if err != nil {
+			return fmt.Errorf("读取配置文件失败: %v", err)
+		}
+		for _, dbConfig := range config.Databases {
+			existing[strings.ToUpper(dbConfig.Name)] = true
+			if schema, err := dbConfig.schemaName(); err == nil {
+				existing[schema] = true
+			}
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("读取配置文件失败: %v", err)
+	}
+
+	var added []string
+	var entries []yaml.MapSlice
+	for _, db := range databases {
+		if len(db.Tables) == 0 || existing[db.Name] || existing[strings.ToUpper(db.Name)] {
+			continue
+		}
+		entries = append(entries, scanConfigEntry(server, db.Name))
+		added = append(added, db.Name)
+	}
+	if len(added) == 0 && data != nil {
+		fmt.Fprintf(os.Stderr, "%s 中已有扫描到的全部数据库，没有修改\n", filename)
+		return nil
+	}
+
+	var out []byte
+	if data == nil {
+		items := make([]interface{}, len(entries))
+		for i, entry := range entries {
+			items[i] = entry
+		}
+		out, err = yaml.Marshal(yaml.MapSlice{{Key: "databases", Value: items}, {Key: "global", Value: scanGlobalConfig()}})
+	} else {
+		out, err = appendConfigEntries(data, entries)
+	}
+	if err != nil {
+		return fmt.Errorf("更新 %s 失败: %v", filename, err)
+	}
+	if err := ioutil.WriteFile(filename, out, 0644); err != nil {
+		return fmt.Errorf("写入配置文件失败: %v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "写入 %s: 新增 %d 个数据库", filename, len(added))
+	if len(added) > 0 {
+		fmt.Fprintf(os.Stderr, " (%s)", strings.Join(added, ", "))
+	}
+	fmt.Fprintln(os.Stderr)
+	return nil
+}
+
+// appendConfigEntries 以文本方式把 entries 追加到配置文件顶层 databases 列表的末尾，保留原有的内容和注释
+// 列表的结束位置为下一个顶层键，其前面的空行和顶格注释属于下一个键；没有 databases 时追加到文件末尾
+func appendConfigEntries(data []byte, entries []yaml.MapSlice) ([]byte, error) {
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	topLevel := func(line string) bool {
+		return strings.TrimSpace(line) != "" && strings.IndexByte(" \t#-", line[0]) < 0
+	}
+
+	header := -1
+	for i, line := range lines {
+		if topLevel(line) && strings.HasPrefix(line, "databases:") {
+			header = i
+			break
+		}
+	}
+	if header < 0 {
+		if len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+			lines[len(lines)-1] += "\n"
+		}
+		lines = append(lines, "databases:\n")
+		header = len(lines) - 1
+	}
+
+	// databases: 之后只能是注释或空列表 []
+	value := strings.TrimRight(lines[header][len("databases:"):], "\r\n")
+	comment := ""
+	if i := strings.Index(value, "#"); i >= 0 {
+		value, comment = value[:i], value[i:]
+	}
+	switch strings.TrimSpace(value) {
+	case "", "[]", "~", "null":
+		if strings.TrimSpace(value) != "" {
+			lines[header] = strings.TrimSpace("databases: "+comment) + "\n"
+		}
+	default:
+		return nil, fmt.Errorf("databases 不是块格式的列表，请手动添加")
+	}
+
+	// 列表结束位置和已有项的缩进
+	end := header + 1
+	for end < len(lines) && !topLevel(lines[end]) {
+		end++
+	}
+	last := header
+	indent := "  "
+	hasItems := false
+	for i := header + 1; i < end; i++ {
+		trimmed := strings.TrimLeft(lines[i], " \t")
+		if strings.TrimSpace(trimmed) == "" || lines[i][0] == '#' {
+			continue
+		}
+		if !hasItems && strings.HasPrefix(trimmed, "-") {
+			indent = lines[i][:len(lines[i])-len(trimmed)]
+			hasItems = true
+		}
+		last = i
+	}
+	if !strings.HasSuffix(lines[last], "\n") {
+		lines[last] += "\n"
+	}
+
+	var b strings.Builder
+	for i, entry := range entries {
+		out, err := yaml.Marshal([]yaml.MapSlice{entry})
+		if err != nil {
+			return nil, fmt.Errorf("序列化配置失败: %v", err)
+		}
+		if hasItems || i > 0 {
+			b.WriteString("\n")
+		}
+		for _, line := range strings.SplitAfter(strings.TrimSuffix(string(out), "\n"), "\n") {
+			b.WriteString(indent + line)
+		}
+		b.WriteString("\n")
+	}
+
+	result := append([]string{}, lines[:last+1]...)
+	result = append(result, b.String())
+	result = append(result, lines[last+1:]...)
+	return []byte(strings.Join(result, "")), nil
+}
+
+// getEnvOrDefault 获取环境变量或返回默认值
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// scanDatabases 扫描数据库
+// 无法读取表或存储过程、导出快照失败的数据库记录到 failed
+func scanDatabases(server scanServer, snapshotDir string, failed failures) ([]DatabaseInfo, error) {
+	// 连接到 MySQL 服务器（不指定数据库）
+	db, err := gorm.Open(mysql.Open(server.dsn("")), scanGormConfig())
+	if err != nil {
+		return nil, fmt.Errorf("连接数据库失败: %v", err)
+	}
+	defer closeDB(db)
+
+	// 获取所有数据库名
+	var databases []string
+	err = db.Raw("SHOW DATABASES").Scan(&databases).Error
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库列表失败: %v", err)
+	}
+
+	// 过滤掉系统数据库
+	var filteredDatabases []string
+	for _, dbName := range databases {
+		if !isSystemDatabase(dbName) {
+			filteredDatabases = append(filteredDatabases, dbName)
+		}
+	}
+
+	// 获取每个数据库的表和存储过程信息
+	var result []DatabaseInfo
+	for _, dbName := range filteredDatabases {
+		tables, err := getTablesInDatabase(server, dbName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "警告: 无法获取数据库 %s 的表信息: %v\n", dbName, err)
+			failed.add(dbName, err)
+			continue
+		}
+
+		procedures, err := getProceduresInDatabase(server, dbName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "警告: 无法获取数据库 %s 的存储过程信息: %v\n", dbName, err)
+			failed.add(dbName, err)
+		}
+		if procedures == nil {
+			procedures = []ProcedureInfo{}
+		}
+
+		if snapshotDir != "" {
+			if err := exportSnapshot(db, dbName, snapshotDir); err != nil {
+				fmt.Fprintf(os.Stderr, "警告: 无法导出数据库 %s 的表结构快照: %v\n", dbName, err)
+				failed.add(dbName, err)
+			}
+		}
+
+		result = append(result, DatabaseInfo{
+			Name:       dbName,
+			Tables:     tables,
+			Procedures: procedures,
+		})
+	}
+
+	return result, nil
+}
+
+// scanGormConfig 扫描时使用的 gorm 配置，查询错误已返回给调用方，不再由 gorm 输出日志
+func scanGormConfig() *gorm.Config {
+	return &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)}
+}
+
+// getTablesInDatabase 获取指定数据库中的表
+func getTablesInDatabase(server scanServer, dbName string) ([]string, error) {
+	db, err := gorm.Open(mysql.Open(server.dsn(dbName)), scanGormConfig())
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB(db)
+
+	tables := []string{}
+	err = db.Raw("SHOW TABLES").Scan(&tables).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+// getProceduresInDatabase 获取指定数据库中的存储过程和存储函数
+func getProceduresInDatabase(server scanServer, dbName string) ([]ProcedureInfo, error) {
+	db, err := gorm.Open(mysql.Open(server.dsn(dbName)), scanGormConfig())
+	if err != nil {
+		return nil, err
+	}
+	defer closeDB(db)
+
+	return getAllProcedures(db, dbName)
+}
+
+// exportSnapshot 读取数据库的表结构快照并写入 <dir>/<dbName>.json
+func exportSnapshot(db *gorm.DB, dbName, dir string) error {
+	snapshot, err := readSnapshot(db, dbName)
+	if err != nil {
+		return err
+	}
+	filePath := filepath.Join(dir, dbName+".json")
+	if err := writeSnapshot(filePath, snapshot); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "导出表结构快照: %s (%d 个表, %d 个存储过程)\n", filePath, len(snapshot.Tables), len(snapshot.Routines))
+	return nil
+}
+
+// isSystemDatabase 判断是否为系统数据库
+func isSystemDatabase(dbName string) bool {
+	systemDBs := []string{
+		"information_schema",
+		"performance_schema",
+		"mysql",
+		"sys",
+		"test",
+	}
+
+	for _, sysDB := range systemDBs {
+		if strings.ToLower(dbName) == sysDB {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestAppendConfigEntries(t *testing.T) {
+	server := scanServer{host: "127.0.0.1", port: "3306", user: "root", password: "pw"}
+	entries := []yaml.MapSlice{scanConfigEntry(server, "new1"), scanConfigEntry(server, "new2")}
+	entry := func(indent, name string) string {
+		return indent + "- name: " + strings.ToUpper(name) + "\n" +
+			indent + "  dsn: " + server.dsn(name) + "\n" +
+			indent + "  out_path: ./models/" + name + "\n" +
+			indent + "  tables: []\n"
+	}
+
+	tests := []struct {
+		name, in, want string
+	}{
+		{
+			"缩进的列表",
+			"# 数据库配置\ndatabases:\n  - name: \"OLD\"  # 旧库\n    dsn: \"x\"\n    tables: []  # 所有表\n\n# 全局配置\nglobal:\n  mode: 7\n",
+			"# 数据库配置\ndatabases:\n  - name: \"OLD\"  # 旧库\n    dsn: \"x\"\n    tables: []  # 所有表\n\n" +
+				entry("  ", "new1") + "\n" + entry("  ", "new2") + "\n# 全局配置\nglobal:\n  mode: 7\n",
+		},
+		{
+			"顶格的列表",
+			"global:\n  mode: 7\ndatabases:\n- name: OLD\n  dsn: x\n  # 末尾的注释\n",
+			"global:\n  mode: 7\ndatabases:\n- name: OLD\n  dsn: x\n  # 末尾的注释\n\n" + entry("", "new1") + "\n" + entry("", "new2"),
+		},
+		{
+			"空列表",
+			"databases: []  # 待扫描\nglobal:\n  mode: 7",
+			"databases: # 待扫描\n" + entry("  ", "new1") + "\n" + entry("  ", "new2") + "global:\n  mode: 7",
+		},
+		{
+			"没有 databases",
+			"global:\n  mode: 7  # 注释",
+			"global:\n  mode: 7  # 注释\ndatabases:\n" + entry("  ", "new1") + "\n" + entry("  ", "new2"),
+		},
+		{
+			"空文件",
+			"",
+			"databases:\n" + entry("  ", "new1") + "\n" + entry("  ", "new2"),
+		},
+	}
+	for _, tt := range tests {
+		out, err := appendConfigEntries([]byte(tt.in), entries)
+		if err != nil {
+			t.Errorf("%s: appendConfigEntries error: %v", tt.name, err)
+			continue
+		}
+		if string(out) != tt.want {
+			t.Errorf("%s: appendConfigEntries =\n%s\nwant\n%s", tt.name, out, tt.want)
+			continue
+		}
+		var config Config
+		if err := yaml.Unmarshal(out, &config); err != nil {
+			t.Errorf("%s: 结果不是合法的 YAML: %v", tt.name, err)
+			continue
+		}
+		n := len(config.Databases)
+		if n < 2 || config.Databases[n-2].Name != "NEW1" || config.Databases[n-1].Name != "NEW2" {
+			t.Errorf("%s: databases = %+v", tt.name, config.Databases)
+		}
+	}
+
+	if _, err := appendConfigEntries([]byte("databases: [{name: OLD}]\n"), entries); err == nil {
+		t.Error("流式列表应返回错误")
+	}
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ShardConfig 分表配置，结构相同的分表合并为一个模型
+type ShardConfig struct {
+	Table   string `yaml:"table"`   // 基础表名，如 lotterylog，模型按基础表生成
+	Pattern string `yaml:"pattern"` // 分表名模式，{id} 表示数字分表键，默认 <table>_{id}
+	Method  string `yaml:"method"`  // 路由方法名，默认 ForShard
+	Key     string `yaml:"key"`     // 路由方法的参数名，默认 id
+}
+
+// shardPattern 返回分表名模式
+func (c ShardConfig) shardPattern() string {
+	if c.Pattern != "" {
+		return c.Pattern
+	}
+	return c.Table + "_{id}"
+}
+
+// regexp 返回匹配分表名的正则，第一个分组为分表键
+func (c ShardConfig) regexp() (*regexp.Regexp, error) {
+	pattern := c.shardPattern()
+	if strings.Count(pattern, "{id}") != 1 {
+		return nil, fmt.Errorf("分表 %s 的 pattern %q 必须包含一个 {id}", c.Table, pattern)
+	}
+	parts := strings.SplitN(pattern, "{id}", 2)
+	return regexp.Compile("^" + regexp.QuoteMeta(parts[0]) + `(\d+)` + regexp.QuoteMeta(parts[1]) + "$")
+}
+
+// format 返回用于 fmt.Sprintf 的分表名格式
+func (c ShardConfig) format() string {
+	return strings.Replace(strings.ReplaceAll(c.shardPattern(), "%", "%%"), "{id}", "%d", 1)
+}
+
+func (c ShardConfig) methodName() string {
+	if c.Method != "" {
+		return c.Method
+	}
+	return "ForShard"
+}
+
+func (c ShardConfig) keyName() string {
+	if c.Key != "" {
+		return c.Key
+	}
+	return "id"
+}
+
+// shardGroup 检测到的一组分表
+type shardGroup struct {
+	Config ShardConfig
+	Keys   []int64  // 结构与基础表相同的分表键，升序
+	Tables []string // 与 Keys 对应的分表名
+}
+
+// detectShards 从 tables 中找出与基础表结构相同的分表
+// 返回的表列表中去掉了已合并的分表；结构不同的分表保留，单独生成模型
+func detectShards(db *gorm.DB, dbConfig DatabaseConfig, tables []string) ([]shardGroup, []string, error) {
+	if len(dbConfig.Shards) == 0 {
+		return nil, tables, nil
+	}
+
+	tableSet := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		tableSet[table] = true
+	}
+
+	merged := make(map[string]bool)
+	var groups []shardGroup
+	for _, shardConfig := range dbConfig.Shards {
+		if shardConfig.Table == "" {
+			return nil, nil, fmt.Errorf("分表配置缺少 table")
+		}
+		if !tableSet[shardConfig.Table] {
+			return nil, nil, fmt.Errorf("分表的基础表 %s 不在生成列表中", shardConfig.Table)
+		}
+		re, err := shardConfig.regexp()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		baseColumns, err := columnSignature(db, shardConfig.Table)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		group := shardGroup{Config: shardConfig}
+		for _, table := range tables {
+			match := re.FindStringSubmatch(table)
+			if match == nil || table == shardConfig.Table {
+				continue
+			}
+			key, err := strconv.ParseInt(match[1], 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("解析分表 %s 的分表键失败: %v", table, err)
+			}
+
+			columns, err := columnSignature(db, table)
+			if err != nil {
+				return nil, nil, err
+			}
+			if columns != baseColumns {
+				fmt.Printf("警告: 分表 %s 与 %s 结构不同，单独生成模型\n", table, shardConfig.Table)
+				continue
+			}
+			group.Keys = append(group.Keys, key)
+			merged[table] = true
+		}
+
+		sort.Slice(group.Keys, func(i, j int) bool { return group.Keys[i] < group.Keys[j] })
+		for _, key := range group.Keys {
+			group.Tables = append(group.Tables, fmt.Sprintf(shardConfig.format(), key))
+		}
+		fmt.Printf("数据库 %s 的 %s 合并了 %d 个分表\n", dbConfig.Name, shardConfig.Table, len(group.Keys))
+		groups = append(groups, group)
+	}
+
+	var remaining []string
+	for _, table := range tables {
+		if !merged[table] {
+			remaining = append(remaining, table)
+		}
+	}
+	return groups, remaining, nil
+}
+
+// columnSignature 返回表的列名和列类型，用于比较分表结构是否相同
+func columnSignature(db *gorm.DB, table string) (string, error) {
+	columnTypes, err := db.Migrator().ColumnTypes(table)
+	if err != nil {
+		return "", fmt.Errorf("获取表 %s 的列信息失败: %v", table, err)
+	}
+
+	var columns []string
+	for _, column := range columnTypes {
+		columnType, _ := column.ColumnType()
+		nullable, _ := column.Nullable()
+		columns = append(columns, fmt.Sprintf("%s %s %t", column.Name(), columnType, nullable))
+	}
+	return strings.Join(columns, ","), nil
+}
+
+// generateShardFile 为分表生成路由方法，写入 <out_path>/<table>_shards.gen.go
+// queryStruct 为 gorm/gen 生成的查询结构体名，如 lotterylog
+func generateShardFile(dbConfig DatabaseConfig, group shardGroup, queryStruct string) error {
+	shardConfig := group.Config
+	receiver := strings.ToLower(queryStruct[:1])
+	method := shardConfig.methodName()
+	key := shardConfig.keyName()
+	knownVar := queryStruct + "Shards"
+	cacheVar := queryStruct + "ShardCache"
+
+	var code strings.Builder
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n")
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n")
+	code.WriteString("// Code generated by gorm.io/gen. DO NOT EDIT.\n\n")
+	code.WriteString(fmt.Sprintf("package %s\n\n", dbConfig.packageName()))
+	code.WriteString("import (\n\t\"fmt\"\n\t\"sync\"\n)\n\n")
+
+	code.WriteString(fmt.Sprintf("// %s 生成代码时 %s 已存在的分表键\n", knownVar, shardConfig.Table))
+	code.WriteString(fmt.Sprintf("var %s = map[int64]bool{", knownVar))
+	for i, shardKey := range group.Keys {
+		if i%10 == 0 {
+			code.WriteString("\n\t")
+		} else {
+			code.WriteString(" ")
+		}
+		code.WriteString(fmt.Sprintf("%d: true,", shardKey))
+	}
+	code.WriteString("\n}\n\n")
+
+	code.WriteString(fmt.Sprintf("// %s 运行时确认存在的分表\n", cacheVar))
+	code.WriteString(fmt.Sprintf("var %s sync.Map\n\n", cacheVar))
+
+	code.WriteString(fmt.Sprintf("// %s 返回路由到分表 %s 的查询\n", method, shardConfig.shardPattern()))
+	code.WriteString("// 分表键不在生成时的列表中时查询数据库确认分表存在，新增分表无需重新生成代码\n")
+	code.WriteString(fmt.Sprintf("func (%s %s) %s(%s int64) (*%s, error) {\n", receiver, queryStruct, method, key, queryStruct))
+	code.WriteString(fmt.Sprintf("\ttableName := fmt.Sprintf(%q, %s)\n", shardConfig.format(), key))
+	code.WriteString(fmt.Sprintf("\tif !%s[%s] {\n", knownVar, key))
+	code.WriteString(fmt.Sprintf("\t\tif _, ok := %s.Load(tableName); !ok {\n", cacheVar))
+	code.WriteString(fmt.Sprintf("\t\t\tif !%s.UnderlyingDB().Migrator().HasTable(tableName) {\n", receiver))
+	code.WriteString(fmt.Sprintf("\t\t\t\treturn nil, fmt.Errorf(\"%s: 分表 %%s 不存在\", tableName)\n", shardConfig.Table))
+	code.WriteString("\t\t\t}\n")
+	code.WriteString(fmt.Sprintf("\t\t\t%s.Store(tableName, true)\n", cacheVar))
+	code.WriteString("\t\t}\n")
+	code.WriteString("\t}\n")
+	code.WriteString(fmt.Sprintf("\treturn %s.Table(tableName), nil\n", receiver))
+	code.WriteString("}\n")
+
+	filePath := filepath.Join(dbConfig.OutPath, shardConfig.Table+"_shards.gen.go")
+	if err := writeGoFile(filePath, code.String()); err != nil {
+		return err
+	}
+	fmt.Printf("生成分表路由: %s (%d 个分表)\n", filePath, len(group.Keys))
+	return nil
+}
@@ -0,0 +1,573 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/migrator"
+)
+
+// snapshotVersion 快照格式的版本，格式不兼容时递增
+const snapshotVersion = 1
+
+// SchemaSnapshot 一个数据库的表结构快照，由 scan -snapshot 导出
+// models、procedures 使用快照时不连接数据库，生成结果与连接数据库时相同
+// 列、索引和外键保存 information_schema 中的原始值，字段含义与 information_schema 相同
+type SchemaSnapshot struct {
+	Version       int             `json:"version"`
+	Schema        string          `json:"schema"`
+	ServerVersion string          `json:"server_version"` // SELECT VERSION()，影响 gorm 读取列信息的方式
+	Charset       string          `json:"charset"`        // schema 默认字符集
+	Collation     string          `json:"collation"`      // schema 默认排序规则
+	Tables        []TableSnapshot `json:"tables"`
+	Routines      []ProcedureInfo `json:"routines"` // 存储过程和存储函数，包含参数
+}
+
+// TableSnapshot 表结构
+type TableSnapshot struct {
+	Name        string               `json:"name"`
+	Type        string               `json:"type"` // BASE TABLE / VIEW
+	Engine      string               `json:"engine,omitempty"`
+	Collation   string               `json:"collation,omitempty"`
+	Comment     string               `json:"comment,omitempty"`
+	Columns     []ColumnSnapshot     `json:"columns"`
+	Indexes     []IndexSnapshot      `json:"indexes,omitempty"`
+	ForeignKeys []ForeignKeySnapshot `json:"foreign_keys,omitempty"`
+}
+
+// ColumnSnapshot 列信息，来自 information_schema.COLUMNS
+type ColumnSnapshot struct {
+	Name              string  `json:"name"`
+	DataType          string  `json:"data_type"`         // 基础类型，如 int、varchar
+	ColumnType        string  `json:"column_type"`       // 完整类型，如 int(11) unsigned
+	Nullable          bool    `json:"nullable"`          // IS_NULLABLE = 'YES'
+	Default           *string `json:"default,omitempty"` // COLUMN_DEFAULT，nil 表示没有默认值
+	Key               string  `json:"key,omitempty"`     // COLUMN_KEY：PRI、UNI、MUL
+	Extra             string  `json:"extra,omitempty"`   // 如 auto_increment
+	Comment           string  `json:"comment,omitempty"`
+	CharMaxLength     *int64  `json:"char_max_length,omitempty"`
+	NumericPrecision  *int64  `json:"numeric_precision,omitempty"`
+	NumericScale      *int64  `json:"numeric_scale,omitempty"`
+	DatetimePrecision *int64  `json:"datetime_precision,omitempty"`
+	Charset           string  `json:"charset,omitempty"`
+	Collation         string  `json:"collation,omitempty"`
+}
+
+// IndexSnapshot 索引，来自 information_schema.STATISTICS，主键索引名为 PRIMARY
+type IndexSnapshot struct {
+	Name    string   `json:"name"`
+	Unique  bool     `json:"unique"`
+	Columns []string `json:"columns"` // 按 SEQ_IN_INDEX 排列，表达式索引的列名为空
+}
+
+// ForeignKeySnapshot 引用同一 schema 中其它表的外键约束，来自 information_schema.KEY_COLUMN_USAGE
+type ForeignKeySnapshot struct {
+	Name       string   `json:"name"`
+	Columns    []string `json:"columns"`
+	RefTable   string   `json:"ref_table"`
+	RefColumns []string `json:"ref_columns"`
+}
+
+// table 按表名查找表，先精确匹配再忽略大小写
+func (s *SchemaSnapshot) table(name string) *TableSnapshot {
+	for i := range s.Tables {
+		if s.Tables[i].Name == name {
+			return &s.Tables[i]
+		}
+	}
+	for i := range s.Tables {
+		if strings.EqualFold(s.Tables[i].Name, name) {
+			return &s.Tables[i]
+		}
+	}
+	return nil
+}
+
+// readSnapshot 从 information_schema 读取 schema 的表结构快照
+func readSnapshot(db *gorm.DB, schema string) (*SchemaSnapshot, error) {
+	snapshot := &SchemaSnapshot{Version: snapshotVersion, Schema: schema}
+	if err := db.Raw("SELECT VERSION()").Row().Scan(&snapshot.ServerVersion); err != nil {
+		return nil, fmt.Errorf("查询服务器版本失败: %v", err)
+	}
+	err := db.Raw(`
+		SELECT DEFAULT_CHARACTER_SET_NAME, DEFAULT_COLLATION_NAME
+		FROM information_schema.SCHEMATA
+		WHERE SCHEMA_NAME = ?
+	`, schema).Row().Scan(&snapshot.Charset, &snapshot.Collation)
+	if err != nil {
+		return nil, fmt.Errorf("查询 schema %s 失败: %v", schema, err)
+	}
+
+	// 表
+	rows, err := db.Raw(`
+		SELECT TABLE_NAME, TABLE_TYPE, COALESCE(ENGINE, ''), COALESCE(TABLE_COLLATION, ''), COALESCE(TABLE_COMMENT, '')
+		FROM information_schema.TABLES
+		WHERE TABLE_SCHEMA = ?
+		ORDER BY TABLE_NAME
+	`, schema).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("查询表失败: %v", err)
+	}
+	for rows.Next() {
+		var table TableSnapshot
+		if err := rows.Scan(&table.Name, &table.Type, &table.Engine, &table.Collation, &table.Comment); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("读取表失败: %v", err)
+		}
+		snapshot.Tables = append(snapshot.Tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取表失败: %v", err)
+	}
+
+	// 列，与 gorm mysql 驱动的 Migrator.ColumnTypes 读取相同的字段
+	rows, err = db.Raw(`
+		SELECT
+			TABLE_NAME, COLUMN_NAME, COLUMN_DEFAULT, IS_NULLABLE = 'YES', DATA_TYPE, COLUMN_TYPE,
+			COLUMN_KEY, EXTRA, COLUMN_COMMENT,
+			CHARACTER_MAXIMUM_LENGTH, NUMERIC_PRECISION, NUMERIC_SCALE, DATETIME_PRECISION,
+			COALESCE(CHARACTER_SET_NAME, ''), COALESCE(COLLATION_NAME, '')
+		FROM information_schema.COLUMNS
+		WHERE TABLE_SCHEMA = ?
+		ORDER BY TABLE_NAME, ORDINAL_POSITION
+	`, schema).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("查询列失败: %v", err)
+	}
+	for rows.Next() {
+		var tableName string
+		var column ColumnSnapshot
+		var defaultValue sql.NullString
+		var length, precision, scale, datetimePrecision sql.NullInt64
+		err := rows.Scan(&tableName, &column.Name, &defaultValue, &column.Nullable, &column.DataType, &column.ColumnType,
+			&column.Key, &column.Extra, &column.Comment,
+			&length, &precision, &scale, &datetimePrecision,
+			&column.Charset, &column.Collation)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("读取列失败: %v", err)
+		}
+		if defaultValue.Valid {
+			column.Default = &defaultValue.String
+		}
+		column.CharMaxLength = nullInt64Ptr(length)
+		column.NumericPrecision = nullInt64Ptr(precision)
+		column.NumericScale = nullInt64Ptr(scale)
+		column.DatetimePrecision = nullInt64Ptr(datetimePrecision)
+		if table := snapshot.table(tableName); table != nil {
+			table.Columns = append(table.Columns, column)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取列失败: %v", err)
+	}
+
+	// 索引，与 gorm mysql 驱动的 Migrator.GetIndexes 顺序相同
+	rows, err = db.Raw(`
+		SELECT TABLE_NAME, INDEX_NAME, NON_UNIQUE, COALESCE(COLUMN_NAME, '')
+		FROM information_schema.STATISTICS
+		WHERE TABLE_SCHEMA = ?
+		ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX
+	`, schema).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("查询索引失败: %v", err)
+	}
+	for rows.Next() {
+		var tableName, indexName, columnName string
+		var nonUnique int
+		if err := rows.Scan(&tableName, &indexName, &nonUnique, &columnName); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("读取索引失败: %v", err)
+		}
+		table := snapshot.table(tableName)
+		if table == nil {
+			continue
+		}
+		if n := len(table.Indexes); n == 0 || table.Indexes[n-1].Name != indexName {
+			table.Indexes = append(table.Indexes, IndexSnapshot{Name: indexName, Unique: nonUnique == 0})
+		}
+		index := &table.Indexes[len(table.Indexes)-1]
+		index.Columns = append(index.Columns, columnName)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取索引失败: %v", err)
+	}
+
+	// 外键
+	rows, err = db.Raw(`
+		SELECT TABLE_NAME, CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+		FROM information_schema.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = ? AND REFERENCED_TABLE_SCHEMA = ? AND REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY TABLE_NAME, CONSTRAINT_NAME, ORDINAL_POSITION
+	`, schema, schema).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("查询外键约束失败: %v", err)
+	}
+	for rows.Next() {
+		var tableName, name, column, refTable, refColumn string
+		if err := rows.Scan(&tableName, &name, &column, &refTable, &refColumn); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("读取外键约束失败: %v", err)
+		}
+		table := snapshot.table(tableName)
+		if table == nil {
+			continue
+		}
+		if n := len(table.ForeignKeys); n == 0 || table.ForeignKeys[n-1].Name != name {
+			table.ForeignKeys = append(table.ForeignKeys, ForeignKeySnapshot{Name: name, RefTable: refTable})
+		}
+		fk := &table.ForeignKeys[len(table.ForeignKeys)-1]
+		fk.Columns = append(fk.Columns, column)
+		fk.RefColumns = append(fk.RefColumns, refColumn)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("读取外键约束失败: %v", err)
+	}
+
+	// 存储过程和存储函数
+	snapshot.Routines, err = getAllProcedures(db, schema)
+	if err != nil {
+		return nil, fmt.Errorf("获取存储过程失败: %v", err)
+	}
+	return snapshot, nil
+}
+
+func nullInt64Ptr(v sql.NullInt64) *int64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Int64
+}
+
+// writeSnapshot 把快照写入 JSON 文件
+func writeSnapshot(filePath string, snapshot *SchemaSnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("序列化快照失败: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return fmt.Errorf("创建快照目录失败: %v", err)
+	}
+	if err := os.WriteFile(filePath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("写入快照失败: %v", err)
+	}
+	return nil
+}
+
+// loadSnapshot 读取快照文件并检查版本，.sql 文件按 mysqldump 导出的表结构解析
+func loadSnapshot(filePath string) (*SchemaSnapshot, error) {
+	if strings.EqualFold(filepath.Ext(filePath), ".sql") {
+		return parseSchemaFile(filePath)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("读取快照失败: %v", err)
+	}
+	var snapshot SchemaSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("解析快照 %s 失败: %v", filePath, err)
+	}
+	if snapshot.Version < 1 || snapshot.Version > snapshotVersion {
+		return nil, fmt.Errorf("快照 %s 的版本 %d 不受支持，当前支持版本 %d，请用 scan -snapshot 重新导出", filePath, snapshot.Version, snapshotVersion)
+	}
+	if snapshot.Schema == "" {
+		return nil, fmt.Errorf("快照 %s 没有 schema", filePath)
+	}
+	return &snapshot, nil
+}
+
+// useSnapshots 让数据库从 dir 中的 <schema>.json 读取表结构，没有 JSON 快照时使用 mysqldump 导出的 <schema>.sql
+// dir 为空时不修改
+func useSnapshots(databases []DatabaseConfig, dir string) error {
+	if dir == "" {
+		return nil
+	}
+	for i := range databases {
+		schema, err := databases[i].schemaName()
+		if err != nil {
+			return fmt.Errorf("数据库 %s: %v", databases[i].Name, err)
+		}
+		databases[i].Snapshot = filepath.Join(dir, schema+".json")
+		if _, err := os.Stat(databases[i].Snapshot); os.IsNotExist(err) {
+			sqlFile := filepath.Join(dir, schema+".sql")
+			if _, err := os.Stat(sqlFile); err == nil {
+				databases[i].Snapshot = sqlFile
+			}
+		}
+	}
+	return nil
+}
+
+// openDatabase 连接数据库；配置了 snapshot 时改为读取快照文件，不连接数据库
+// 使用快照且没有配置 schema 时，schema 取快照中的名称
+func openDatabase(dbConfig *DatabaseConfig) (*gorm.DB, error) {
+	if dbConfig.Snapshot == "" {
+		db, err := gorm.Open(mysql.Open(dbConfig.DSN), &gorm.Config{})
+		if err != nil {
+			return nil, fmt.Errorf("连接数据库失败: %v", err)
+		}
+		return db, nil
+	}
+
+	snapshot, err := loadSnapshot(dbConfig.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if dbConfig.Schema == "" {
+		dbConfig.Schema = snapshot.Schema
+	}
+	return openSnapshot(snapshot)
+}
+
+// openSnapshot 返回以快照代替数据库连接的 gorm.DB
+func openSnapshot(snapshot *SchemaSnapshot) (*gorm.DB, error) {
+	dialector := snapshotDialector{
+		Dialector: mysql.Dialector{Config: &mysql.Config{ServerVersion: snapshot.ServerVersion, SkipInitializeWithVersion: true}},
+		snapshot:  snapshot,
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		return nil, fmt.Errorf("打开快照失败: %v", err)
+	}
+	return db, nil
+}
+
+// printSnapshotSource 使用快照时输出快照文件
+func printSnapshotSource(dbConfig DatabaseConfig, db *gorm.DB) {
+	if snapshot := snapshotOf(db); snapshot != nil {
+		fmt.Printf("数据库 %s 使用表结构快照 %s (schema %s)\n", dbConfig.Name, dbConfig.Snapshot, snapshot.Schema)
+	}
+}
+
+// snapshotOf 返回 db 使用的快照，连接数据库时返回 nil
+func snapshotOf(db *gorm.DB) *SchemaSnapshot {
+	if dialector, ok := db.Dialector.(snapshotDialector); ok {
+		return dialector.snapshot
+	}
+	return nil
+}
+
+// errSnapshotQuery 使用快照时执行了 SQL 查询
+var errSnapshotQuery = errors.New("使用表结构快照时不能执行 SQL 查询")
+
+// snapshotDialector 以快照代替数据库连接的 gorm 方言
+// 方言名与 mysql 相同，gorm/gen 按 MySQL 的类型映射生成代码；表结构通过 Migrator 从快照读取，其它查询都返回 errSnapshotQuery
+type snapshotDialector struct {
+	mysql.Dialector
+	snapshot *SchemaSnapshot
+}
+
+func (d snapshotDialector) Initialize(db *gorm.DB) error {
+	db.ConnPool = sql.OpenDB(snapshotConnector{})
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+	return nil
+}
+
+func (d snapshotDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return snapshotMigrator{Migrator: d.Dialector.Migrator(db).(mysql.Migrator), snapshot: d.snapshot}
+}
+
+// snapshotConnector 总是连接失败的 driver.Connector
+type snapshotConnector struct{}
+
+func (snapshotConnector) Connect(context.Context) (driver.Conn, error) { return nil, errSnapshotQuery }
+
+func (snapshotConnector) Driver() driver.Driver { return snapshotDriver{} }
+
+type snapshotDriver struct{}
+
+func (snapshotDriver) Open(string) (driver.Conn, error) { return nil, errSnapshotQuery }
+
+// snapshotMigrator 从快照读取表结构，结果与 gorm mysql 驱动的 Migrator 相同
+type snapshotMigrator struct {
+	mysql.Migrator
+	snapshot *SchemaSnapshot
+}
+
+func (m snapshotMigrator) CurrentDatabase() string {
+	return m.snapshot.Schema
+}
+
+func (m snapshotMigrator) GetTables() ([]string, error) {
+	tables := make([]string, len(m.snapshot.Tables))
+	for i, table := range m.snapshot.Tables {
+		tables[i] = table.Name
+	}
+	return tables, nil
+}
+
+func (m snapshotMigrator) HasTable(value interface{}) bool {
+	table, err := m.snapshotTable(value)
+	return err == nil && table != nil
+}
+
+func (m snapshotMigrator) ColumnTypes(value interface{}) ([]gorm.ColumnType, error) {
+	table, err := m.snapshotTable(value)
+	if err != nil {
+		return nil, err
+	}
+	columnTypes := make([]gorm.ColumnType, len(table.Columns))
+	for i, column := range table.Columns {
+		columnTypes[i] = column.gormColumnType(m.snapshot.ServerVersion)
+	}
+	return columnTypes, nil
+}
+
+func (m snapshotMigrator) GetIndexes(value interface{}) ([]gorm.Index, error) {
+	table, err := m.snapshotTable(value)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]gorm.Index, len(table.Indexes))
+	for i, index := range table.Indexes {
+		indexes[i] = &migrator.Index{
+			TableName:       table.Name,
+			NameValue:       index.Name,
+			ColumnList:      index.Columns,
+			PrimaryKeyValue: sql.NullBool{Bool: index.Name == "PRIMARY", Valid: true},
+			UniqueValue:     sql.NullBool{Bool: index.Unique, Valid: true},
+		}
+	}
+	return indexes, nil
+}
+
+// snapshotTable 返回表名或模型对应的快照中的表
+func (m snapshotMigrator) snapshotTable(value interface{}) (*TableSnapshot, error) {
+	var name string
+	err := m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		name = stmt.Table
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	table := m.snapshot.table(name)
+	if table == nil {
+		return nil, fmt.Errorf("快照 %s 中没有表 %s", m.snapshot.Schema, name)
+	}
+	return table, nil
+}
+
+// snapshotColumnType 快照中的列，没有底层的 sql.ColumnType，长度和精度缺失时直接返回不可用
+type snapshotColumnType struct {
+	columnTypeValue
+}
+
+// columnTypeValue 用别名嵌入，字段名不与 ColumnType 方法冲突
+type columnTypeValue = migrator.ColumnType
+
+func (ct snapshotColumnType) Length() (int64, bool) {
+	return ct.LengthValue.Int64, ct.LengthValue.Valid
+}
+
+func (ct snapshotColumnType) DecimalSize() (int64, int64, bool) {
+	return ct.DecimalSizeValue.Int64, ct.ScaleValue.Int64, ct.DecimalSizeValue.Valid
+}
+
+// gormColumnType 按 gorm mysql 驱动 Migrator.ColumnTypes 的规则转换为 gorm.ColumnType
+func (c ColumnSnapshot) gormColumnType(serverVersion string) gorm.ColumnType {
+	ct := migrator.ColumnType{
+		NameValue:        sql.NullString{String: c.Name, Valid: true},
+		DataTypeValue:    sql.NullString{String: c.DataType, Valid: true},
+		ColumnTypeValue:  sql.NullString{String: c.ColumnType, Valid: true},
+		NullableValue:    sql.NullBool{Bool: c.Nullable, Valid: true},
+		CommentValue:     sql.NullString{String: c.Comment, Valid: true},
+		PrimaryKeyValue:  sql.NullBool{Bool: c.Key == "PRI", Valid: true},
+		UniqueValue:      sql.NullBool{Bool: c.Key == "UNI", Valid: true},
+		LengthValue:      nullInt64(c.CharMaxLength),
+		DecimalSizeValue: nullInt64(c.NumericPrecision),
+		ScaleValue:       nullInt64(c.NumericScale),
+		ScanTypeValue:    mysqlScanType(c.DataType, c.Nullable, strings.Contains(strings.ToLower(c.ColumnType), "unsigned")),
+	}
+	if strings.Contains(c.Extra, "auto_increment") {
+		ct.AutoIncrementValue = sql.NullBool{Bool: true, Valid: true}
+	}
+	if c.Default != nil {
+		ct.DefaultValueValue = sql.NullString{String: strings.Trim(*c.Default, "'"), Valid: true}
+		// MariaDB 中没有默认值的列 COLUMN_DEFAULT 为字符串 NULL
+		if strings.Contains(serverVersion, "MariaDB") && ct.DefaultValueValue.String == "NULL" {
+			ct.DefaultValueValue = sql.NullString{}
+		}
+	}
+	// MySQL 5.5 及更早的版本没有 DATETIME_PRECISION，驱动不读取
+	if c.DatetimePrecision != nil && !isOldMySQL(serverVersion) {
+		ct.DecimalSizeValue = sql.NullInt64{Int64: *c.DatetimePrecision, Valid: true}
+	}
+	return snapshotColumnType{ct}
+}
+
+func nullInt64(v *int64) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: *v, Valid: true}
+}
+
+// isOldMySQL 判断是否为 gorm mysql 驱动禁用日期时间精度的 5.6 以前的版本
+func isOldMySQL(serverVersion string) bool {
+	return strings.HasPrefix(serverVersion, "5.") &&
+		!strings.HasPrefix(serverVersion, "5.6.") && !strings.HasPrefix(serverVersion, "5.7.") &&
+		!strings.Contains(serverVersion, "MariaDB")
+}
+
+// mysqlScanType 返回 MySQL 驱动扫描该类型的列时使用的 Go 类型，与 go-sql-driver/mysql 一致
+// gorm/gen 根据它判断默认值是否需要生成 default 标签
+func mysqlScanType(dataType string, nullable, unsigned bool) reflect.Type {
+	integer := func(signed, unsignedType reflect.Type) reflect.Type {
+		switch {
+		case nullable:
+			return reflect.TypeOf(sql.NullInt64{})
+		case unsigned:
+			return unsignedType
+		default:
+			return signed
+		}
+	}
+	switch strings.ToLower(dataType) {
+	case "tinyint":
+		return integer(reflect.TypeOf(int8(0)), reflect.TypeOf(uint8(0)))
+	case "smallint", "year":
+		return integer(reflect.TypeOf(int16(0)), reflect.TypeOf(uint16(0)))
+	case "mediumint", "int", "integer":
+		return integer(reflect.TypeOf(int32(0)), reflect.TypeOf(uint32(0)))
+	case "bigint":
+		return integer(reflect.TypeOf(int64(0)), reflect.TypeOf(uint64(0)))
+	case "float":
+		if nullable {
+			return reflect.TypeOf(sql.NullFloat64{})
+		}
+		return reflect.TypeOf(float32(0))
+	case "double", "real":
+		if nullable {
+			return reflect.TypeOf(sql.NullFloat64{})
+		}
+		return reflect.TypeOf(float64(0))
+	case "date", "datetime", "timestamp":
+		return reflect.TypeOf(sql.NullTime{})
+	case "decimal", "numeric", "char", "varchar", "binary", "varbinary", "bit", "enum", "set", "json", "time",
+		"tinytext", "text", "mediumtext", "longtext", "tinyblob", "blob", "mediumblob", "longblob",
+		"geometry", "point", "linestring", "polygon", "multipoint", "multilinestring", "multipolygon", "geometrycollection":
+		return reflect.TypeOf(sql.RawBytes{})
+	default:
+		return reflect.TypeOf(new(interface{}))
+	}
+}
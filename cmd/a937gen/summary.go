@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// databaseResult 单个数据库的生成结果，用于汇总表
+type databaseResult struct {
+	Name     string
+	Tables   int           // 生成模型的表数
+	Files    int           // 新建或改写的文件数
+	Duration time.Duration // 连接、读取表结构和生成的总耗时
+	Err      error
+}
+
+// fileTimes 记录目录下所有文件的修改时间，用于统计生成时写入的文件
+type fileTimes map[string]time.Time
+
+// readFileTimes 读取多个目录下所有文件的修改时间，不存在的目录跳过
+func readFileTimes(dirs ...string) fileTimes {
+	times := make(fileTimes)
+	for _, dir := range dirs {
+		filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				times[path] = info.ModTime()
+			}
+			return nil
+		})
+	}
+	return times
+}
+
+// written 返回与之前相比新建或修改过的文件数
+func (before fileTimes) written(dirs ...string) int {
+	count := 0
+	for path, modTime := range readFileTimes(dirs...) {
+		if prev, ok := before[path]; !ok || !prev.Equal(modTime) {
+			count++
+		}
+	}
+	return count
+}
+
+// printSummary 输出每个数据库的生成结果
+func printSummary(results []databaseResult, elapsed time.Duration) {
+	header := []string{"数据库", "表", "文件", "耗时"}
+	rows := make([][]string, len(results))
+	widths := make([]int, len(header))
+	for i, cell := range header {
+		widths[i] = displayWidth(cell)
+	}
+	failedCount := 0
+	for i, result := range results {
+		rows[i] = []string{
+			result.Name,
+			fmt.Sprint(result.Tables),
+			fmt.Sprint(result.Files),
+			result.Duration.Round(10 * time.Millisecond).String(),
+		}
+		for j, cell := range rows[i] {
+			if w := displayWidth(cell); w > widths[j] {
+				widths[j] = w
+			}
+		}
+		if result.Err != nil {
+			failedCount++
+		}
+	}
+
+	line := func(cells []string, status string) {
+		var b strings.Builder
+		for i, cell := range cells {
+			b.WriteString(cell)
+			b.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)+2))
+		}
+		b.WriteString(status)
+		fmt.Println(b.String())
+	}
+
+	fmt.Println("\n生成结果:")
+	line(header, "结果")
+	for i, result := range results {
+		status := "成功"
+		if result.Err != nil {
+			// 只显示错误的第一行，完整的错误已在生成时输出
+			status = "失败: " + strings.SplitN(result.Err.Error(), "\n", 2)[0]
+		}
+		line(rows[i], status)
+	}
+	fmt.Printf("共 %d 个数据库，成功 %d 个，失败 %d 个，总耗时 %s\n",
+		len(results), len(results)-failedCount, failedCount, elapsed.Round(10*time.Millisecond))
+}
+
+// displayWidth 返回字符串在终端中的显示宽度，中文等宽字符按 2 计算
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if utf8.RuneLen(r) >= 3 {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
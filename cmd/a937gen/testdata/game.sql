@@ -0,0 +1,54 @@
+-- MySQL dump 10.13  Distrib 8.0.36, for Linux (x86_64)
+--
+-- Host: localhost    Database: 
+-- ------------------------------------------------------
+-- Server version	8.0.36
+
+/*!40101 SET NAMES utf8mb4 */;
+
+--
+-- Current Database: `game`
+--
+
+CREATE DATABASE /*!32312 IF NOT EXISTS*/ `game` /*!40100 DEFAULT CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci */ /*!80016 DEFAULT ENCRYPTION='N' */;
+
+USE `game`;
+
+--
+-- Table structure for table `t_rooms`
+--
+
+DROP TABLE IF EXISTS `t_rooms`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `t_rooms` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `name` varchar(50) NOT NULL COMMENT '房间名',
+  `state` tinyint NOT NULL DEFAULT '0' COMMENT '状态 0:等待 1:进行中 2:结束',
+  `blind` decimal(10,2) DEFAULT NULL COMMENT '小盲注',
+  `total` int GENERATED ALWAYS AS ((`id` * 2)) VIRTUAL,
+  `created_at` datetime(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+  `updated_at` datetime DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+  `uuid` varchar(36) NOT NULL DEFAULT (uuid()),
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_name` (`name`),
+  KEY `idx_state_time` (`state`,`created_at` DESC)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='房间';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `t_games`
+--
+
+DROP TABLE IF EXISTS `t_games`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `t_games` (
+  `room_id` int unsigned NOT NULL,
+  `seq` int NOT NULL,
+  `result` tinyint DEFAULT NULL COMMENT '结果 0:输 1:赢 2:和',
+  `title` varchar(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL,
+  UNIQUE KEY `room_seq` (`room_id`,`seq`),
+  CONSTRAINT `fk_games_room` FOREIGN KEY (`room_id`) REFERENCES `t_rooms` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
@@ -0,0 +1,113 @@
+-- MySQL dump 10.13  Distrib 5.7.44, for Linux (x86_64)
+--
+-- Host: 127.0.0.1    Database: gameaccount
+-- ------------------------------------------------------
+-- Server version	5.7.44-log
+
+/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
+/*!40101 SET NAMES utf8 */;
+/*!40103 SET @OLD_TIME_ZONE=@@TIME_ZONE */;
+/*!40103 SET TIME_ZONE='+00:00' */;
+/*!40014 SET @OLD_UNIQUE_CHECKS=@@UNIQUE_CHECKS, UNIQUE_CHECKS=0 */;
+/*!40101 SET @OLD_SQL_MODE=@@SQL_MODE, SQL_MODE='NO_AUTO_VALUE_ON_ZERO' */;
+
+--
+-- Table structure for table `newuseraccounts`
+--
+
+DROP TABLE IF EXISTS `newuseraccounts`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `newuseraccounts` (
+  `Id` int(11) NOT NULL AUTO_INCREMENT COMMENT '用户ID',
+  `Account` varchar(32) NOT NULL DEFAULT '' COMMENT '账号; 不能重复',
+  `nickname` varchar(64) CHARACTER SET gbk DEFAULT NULL COMMENT 'it''s the player\'s name',
+  `score` decimal(12,2) unsigned NOT NULL DEFAULT '0.00' COMMENT '金币',
+  `is_online` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否在线(0:否 1:是)',
+  `fromtype` tinyint(1) unsigned DEFAULT '1' COMMENT '1-5',
+  `ratio` float NOT NULL DEFAULT '-1',
+  `state` enum('normal','frozen') NOT NULL DEFAULT 'normal',
+  `flags` bit(8) DEFAULT b'0',
+  `remark` text,
+  `addtime` datetime DEFAULT CURRENT_TIMESTAMP,
+  `updatetime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`Id`),
+  UNIQUE KEY `Account` (`Account`),
+  KEY `idx_score` (`score`,`Id`)
+) ENGINE=InnoDB AUTO_INCREMENT=100001 DEFAULT CHARSET=utf8mb4 COMMENT='用户账号';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `scoreout`
+--
+
+DROP TABLE IF EXISTS `scoreout`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `scoreout` (
+  `id` bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+  `userid` int(11) NOT NULL,
+  `state` tinyint(4) NOT NULL DEFAULT '0' COMMENT '0未处理,1已处理',
+  `memo` varchar(255) COLLATE latin1_bin DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `userid` (`userid`),
+  CONSTRAINT `scoreout_ibfk_1` FOREIGN KEY (`userid`) REFERENCES `newuseraccounts` (`Id`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Temporary table structure for view `v_online`
+--
+
+DROP TABLE IF EXISTS `v_online`;
+/*!50001 DROP VIEW IF EXISTS `v_online`*/;
+SET @saved_cs_client     = @@character_set_client;
+SET character_set_client = utf8;
+/*!50001 CREATE VIEW `v_online` AS SELECT 
+ 1 AS `Id`,
+ 1 AS `Account`*/;
+SET character_set_client = @saved_cs_client;
+
+--
+-- Dumping routines for database 'gameaccount'
+--
+/*!50003 DROP PROCEDURE IF EXISTS `sp_add_score` */;
+/*!50003 SET @saved_cs_client      = @@character_set_client */ ;
+/*!50003 SET character_set_client  = utf8 */ ;
+/*!50003 SET @saved_sql_mode       = @@sql_mode */ ;
+/*!50003 SET sql_mode              = 'NO_AUTO_VALUE_ON_ZERO' */ ;
+DELIMITER ;;
+CREATE DEFINER=`root`@`%` PROCEDURE `sp_add_score`(IN `p_userid` INT, IN p_score DECIMAL(12,2), OUT p_result varchar(64))
+BEGIN
+  -- 分号不结束过程体;
+  UPDATE newuseraccounts SET score = score + p_score WHERE Id = p_userid;
+  SET p_result = 'it''s done; ok';
+  SELECT p_result, 'a\';b' AS escaped;
+END ;;
+DELIMITER ;
+/*!50003 SET sql_mode              = @saved_sql_mode */ ;
+/*!50003 DROP FUNCTION IF EXISTS `fn_score` */;
+DELIMITER ;;
+CREATE DEFINER=`root`@`%` FUNCTION `fn_score`(uid int) RETURNS decimal(12,2)
+    READS SQL DATA
+    COMMENT '查询金币'
+BEGIN
+  DECLARE s DECIMAL(12,2);
+  SELECT score INTO s FROM newuseraccounts WHERE Id = uid;
+  RETURN s;
+END ;;
+DELIMITER ;
+
+--
+-- Final view structure for view `v_online`
+--
+
+/*!50001 DROP VIEW IF EXISTS `v_online`*/;
+/*!50001 SET @saved_cs_client          = @@character_set_client */;
+/*!50001 CREATE ALGORITHM=UNDEFINED */
+/*!50013 DEFINER=`root`@`%` SQL SECURITY DEFINER */
+/*!50001 VIEW `v_online` AS select `newuseraccounts`.`Id` AS `Id`,`newuseraccounts`.`Account` AS `Account` from `newuseraccounts` where (`newuseraccounts`.`is_online` = 1) */;
+/*!50001 SET character_set_client      = @saved_cs_client */;
+
+/*!40103 SET TIME_ZONE=@OLD_TIME_ZONE */;
+-- Dump completed on 2024-05-01 10:00:00
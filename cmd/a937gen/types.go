@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"gorm.io/gen"
+	"gorm.io/gorm"
+)
+
+// typesPkgPath 自定义字段类型所在的包
+const typesPkgPath = "github.com/a937wzgl/a937wzgl_models/types"
+
+// dataTypeMap 返回覆盖 gorm/gen 默认类型映射的规则
+func dataTypeMap(options GenOptions) map[string]func(columnType gorm.ColumnType) string {
+	typeMap := map[string]func(columnType gorm.ColumnType) string{
+		"tinyint": tinyintType,
+	}
+	if boolValue(options.Decimal) {
+		typeMap["decimal"] = func(gorm.ColumnType) string { return "types.Decimal" }
+	}
+	return typeMap
+}
+
+// rangeCommentPattern 匹配 "1-5"、"等级 1~5" 形式的取值范围注释
+var rangeCommentPattern = regexp.MustCompile(`^\D*?(\d+)\s*[-~～]\s*(\d+)\D*$`)
+
+// tinyintType gorm/gen 把所有 tinyint(1) 映射为 bool，
+// 但注释列出了两个以上取值、0/1 以外取值或 0-1 以外取值范围的列实际存的是小整数，映射为 bool 会丢失取值
+func tinyintType(columnType gorm.ColumnType) string {
+	detail, _ := columnType.ColumnType()
+	if !strings.HasPrefix(strings.ToLower(strings.TrimSpace(detail)), "tinyint(1)") {
+		return "int32"
+	}
+	if comment, ok := columnType.Comment(); ok {
+		if values, ok := parseEnumComment(comment); ok && !isBoolValues(values) {
+			return "int8"
+		}
+		if match := rangeCommentPattern.FindStringSubmatch(comment); match != nil && (match[1] != "0" || match[2] != "1") {
+			return "int8"
+		}
+	}
+	return "bool"
+}
+
+// isBoolValues 判断枚举取值是否只有 0 和 1
+func isBoolValues(values []EnumValue) bool {
+	if len(values) > 2 {
+		return false
+	}
+	for _, v := range values {
+		if v.Value != 0 && v.Value != 1 {
+			return false
+		}
+	}
+	return true
+}
+
+// columnTypeOpts 返回表中按 column_types 配置覆盖字段类型的模型选项
+func columnTypeOpts(dbConfig DatabaseConfig, table string) []gen.ModelOpt {
+	var opts []gen.ModelOpt
+	for key, goType := range dbConfig.ColumnTypes {
+		t, column := splitColumnKey(key)
+		if t == table {
+			opts = append(opts, gen.FieldType(column, goType))
+		}
+	}
+	return opts
+}
+
+// splitColumnKey 拆分 "表名.列名" 形式的配置键
+func splitColumnKey(key string) (table, column string) {
+	parts := strings.SplitN(key, ".", 2)
+	if len(parts) != 2 {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
+
+// validColumnKey 检查 "表名.列名" 形式的配置键
+func validColumnKey(key string) error {
+	if table, column := splitColumnKey(key); table == "" || column == "" {
+		return fmt.Errorf("列配置 %q 必须是 表名.列名 的形式", key)
+	}
+	return nil
+}
+
+// matchColumn 判断列是否匹配 "表名.列名" 形式的模式，两部分都支持通配符，省略表名时匹配所有表
+func matchColumn(pattern, table, column string) bool {
+	tablePattern, columnPattern := "*", pattern
+	if strings.Contains(pattern, ".") {
+		tablePattern, columnPattern = splitColumnKey(pattern)
+	}
+	tableOK, _ := path.Match(tablePattern, table)
+	columnOK, _ := path.Match(strings.ToLower(columnPattern), strings.ToLower(column))
+	return tableOK && columnOK
+}
+
+// matchAnyColumn 判断列是否匹配任一模式
+func matchAnyColumn(patterns []string, table, column string) bool {
+	for _, pattern := range patterns {
+		if matchColumn(pattern, table, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// unixTimeOpt 返回把匹配 unix_time_columns 的列替换为 types.UnixTime 的模型选项
+// 整数列使用 UnixTime，字符串列使用 UnixTimeString，存储格式保持不变
+func unixTimeOpt(patterns []string, table string) gen.ModelOpt {
+	return gen.FieldModify(func(f gen.Field) gen.Field {
+		if !matchAnyColumn(patterns, table, f.ColumnName) {
+			return f
+		}
+
+		base := strings.TrimLeft(f.Type, "*")
+		pointer := f.Type[:len(f.Type)-len(base)]
+		switch {
+		case isIntegerType(base):
+			f.Type = pointer + "types.UnixTime"
+		case base == "string":
+			f.Type = pointer + "types.UnixTimeString"
+		default:
+			fmt.Printf("警告: %s.%s 的类型 %s 不能作为 Unix 时间\n", table, f.ColumnName, f.Type)
+		}
+		return f
+	})
+}
+
+// decimalOpt 返回把匹配 decimal_columns 的浮点列替换为 types.Decimal 的模型选项
+// gorm/gen 把 DECIMAL 映射为 float64，这里同样处理 FLOAT/DOUBLE 列
+func decimalOpt(patterns []string, table string) gen.ModelOpt {
+	return gen.FieldModify(func(f gen.Field) gen.Field {
+		base := strings.TrimLeft(f.Type, "*")
+		if base == "types.Decimal" || !matchAnyColumn(patterns, table, f.ColumnName) {
+			return f
+		}
+
+		pointer := f.Type[:len(f.Type)-len(base)]
+		if base == "float64" || base == "float32" {
+			f.Type = pointer + "types.Decimal"
+		} else {
+			fmt.Printf("警告: %s.%s 的类型 %s 不能作为 Decimal\n", table, f.ColumnName, f.Type)
+		}
+		return f
+	})
+}
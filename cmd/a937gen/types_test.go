@@ -0,0 +1,36 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+
+	"gorm.io/gorm/migrator"
+)
+
+func TestTinyintType(t *testing.T) {
+	tests := []struct {
+		columnType, comment, want string
+	}{
+		{"tinyint(1)", "", "bool"},
+		{"tinyint(1)", "是否在线(0:否 1:是)", "bool"},
+		{"tinyint(1)", "1是  0不是", "bool"},
+		{"tinyint(1)", "0-1", "bool"},
+		{"tinyint(1) unsigned", "是否删除", "bool"},
+		{"tinyint(1)", "状态 0:等待 1:进行中 2:结束", "int8"},
+		{"tinyint(1)", "1开启 2关闭", "int8"},
+		{"tinyint(1) unsigned", "1-5", "int8"},
+		{"tinyint(1)", "等级 1~5", "int8"},
+		{"tinyint(1)", "0-9级", "int8"},
+		{"tinyint(4)", "是否在线(0:否 1:是)", "int32"},
+		{"TINYINT(1)", "", "bool"},
+	}
+	for _, tt := range tests {
+		column := migrator.ColumnType{
+			ColumnTypeValue: sql.NullString{String: tt.columnType, Valid: true},
+			CommentValue:    sql.NullString{String: tt.comment, Valid: tt.comment != ""},
+		}
+		if got := tinyintType(column); got != tt.want {
+			t.Errorf("tinyintType(%s, %q) = %s, want %s", tt.columnType, tt.comment, got, tt.want)
+		}
+	}
+}
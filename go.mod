@@ -3,16 +3,24 @@ module github.com/a937wzgl/a937wzgl_models
 go 1.25.1
 
 require (
+	github.com/go-sql-driver/mysql v1.7.0
+	github.com/jinzhu/inflection v1.0.0
+	golang.org/x/text v0.13.0
+	golang.org/x/tools v0.6.0
 	gopkg.in/yaml.v2 v2.4.0
+	gorm.io/datatypes v1.1.1-0.20230130040222-c43177d3cf8c
 	gorm.io/driver/mysql v1.5.2
 	gorm.io/driver/postgres v1.5.4
 	gorm.io/driver/sqlite v1.5.4
 	gorm.io/gen v0.3.23
 	gorm.io/gorm v1.25.5
+	gorm.io/plugin/dbresolver v1.3.0
 )
 
 require (
-	github.com/jinzhu/inflection v1.0.0 // indirect
 	github.com/jinzhu/now v1.1.5 // indirect
-	gorm.io/plugin/dbresolver v1.3.0 // indirect
+	github.com/mattn/go-sqlite3 v1.14.17 // indirect
+	golang.org/x/mod v0.8.0 // indirect
+	golang.org/x/sys v0.5.0 // indirect
+	gorm.io/hints v1.1.0 // indirect
 )
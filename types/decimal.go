@@ -0,0 +1,348 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal 定点小数，对应 MySQL 的 DECIMAL 列，避免 float64 的舍入误差
+// 值为 value × 10^-scale，零值表示 0；所有运算都返回新值，不修改接收者
+type Decimal struct {
+	value *big.Int
+	scale int32
+}
+
+var bigTen = big.NewInt(10)
+
+// ParseDecimal 接受的最大有效位数和小数位数，与 MySQL DECIMAL(65,30) 一致
+// 限制来自 JSON 等外部输入的值，避免 "1e10000000" 这样的输入构造巨大的整数
+const (
+	maxDecimalDigits = 65
+	maxDecimalScale  = 30
+)
+
+// NewDecimal 创建 value × 10^-scale，如 NewDecimal(1234, 2) 为 12.34
+func NewDecimal(value int64, scale int32) Decimal {
+	if scale < 0 {
+		return Decimal{value: new(big.Int).Mul(big.NewInt(value), pow10(-scale))}
+	}
+	return Decimal{value: big.NewInt(value), scale: scale}
+}
+
+// NewDecimalFromInt 创建整数值
+func NewDecimalFromInt(value int64) Decimal {
+	return NewDecimal(value, 0)
+}
+
+// NewDecimalFromFloat 按 float64 的最短十进制表示创建，只用于和旧代码交互
+// NaN、无穷大和超出 DECIMAL(65,30) 范围的值返回 0
+func NewDecimalFromFloat(value float64) Decimal {
+	d, err := parseFloat(value, 64)
+	if err != nil {
+		return Decimal{}
+	}
+	return d
+}
+
+// parseFloat 按 bitSize 位浮点数的最短十进制表示创建，float32 的值按 32 位格式化才不会带出二进制误差
+func parseFloat(value float64, bitSize int) (Decimal, error) {
+	return ParseDecimal(strconv.FormatFloat(value, 'f', -1, bitSize))
+}
+
+// ParseDecimal 解析十进制字符串，如 "-12.50"、"1e3"
+// 有效位数超过 65 或小数位数超过 30（即 MySQL DECIMAL 的上限）时返回错误
+func ParseDecimal(s string) (Decimal, error) {
+	str := strings.TrimSpace(s)
+	exp := int64(0)
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(str[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("无效的小数 %q", s)
+		}
+		exp, str = e, str[:i]
+	}
+
+	digits := str
+	scale := int64(0)
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		digits = str[:i] + str[i+1:]
+		scale = int64(len(str) - i - 1)
+	}
+	unsigned := strings.TrimLeft(digits, "+-")
+	if unsigned == "" || len(digits)-len(unsigned) > 1 || strings.Trim(unsigned, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("无效的小数 %q", s)
+	}
+	// 先按位数检查范围，再构造整数
+	scale -= exp
+	significant := strings.TrimLeft(unsigned, "0")
+	intDigits, fracDigits := int64(len(significant))-scale, scale
+	if intDigits < 0 {
+		intDigits = 0
+	}
+	if fracDigits < 0 {
+		fracDigits = 0
+	}
+	if scale > maxDecimalScale || intDigits+fracDigits > maxDecimalDigits {
+		return Decimal{}, fmt.Errorf("小数 %q 超出范围，最多 %d 位数字、%d 位小数", s, maxDecimalDigits, maxDecimalScale)
+	}
+	if significant == "" {
+		significant = "0"
+	}
+	value, ok := new(big.Int).SetString(digits[:len(digits)-len(unsigned)]+significant, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("无效的小数 %q", s)
+	}
+
+	if scale < 0 {
+		value.Mul(value, pow10(int32(-scale)))
+		scale = 0
+	}
+	return Decimal{value: value, scale: int32(scale)}, nil
+}
+
+// MustParseDecimal 解析十进制字符串，失败时 panic，用于常量
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// int 返回未缩放的整数值，零值返回 0
+func (d Decimal) int() *big.Int {
+	if d.value == nil {
+		return new(big.Int)
+	}
+	return d.value
+}
+
+// rescale 返回按 scale 缩放后的整数值，scale 不能小于 d.scale
+func (d Decimal) rescale(scale int32) *big.Int {
+	if scale == d.scale {
+		return new(big.Int).Set(d.int())
+	}
+	return new(big.Int).Mul(d.int(), pow10(scale-d.scale))
+}
+
+// Scale 返回小数位数
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Sign 返回 -1、0 或 1
+func (d Decimal) Sign() int {
+	return d.int().Sign()
+}
+
+// IsZero 判断是否为 0
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp 比较两个值，返回 -1、0 或 1
+func (d Decimal) Cmp(other Decimal) int {
+	scale := maxScale(d, other)
+	return d.rescale(scale).Cmp(other.rescale(scale))
+}
+
+// Equal 判断数值是否相等，忽略小数位数，如 1.50 等于 1.5
+func (d Decimal) Equal(other Decimal) bool {
+	return d.Cmp(other) == 0
+}
+
+// Neg 返回相反数
+func (d Decimal) Neg() Decimal {
+	return Decimal{value: new(big.Int).Neg(d.int()), scale: d.scale}
+}
+
+// Abs 返回绝对值
+func (d Decimal) Abs() Decimal {
+	return Decimal{value: new(big.Int).Abs(d.int()), scale: d.scale}
+}
+
+// Add 返回 d + other，小数位数取两者较大值
+func (d Decimal) Add(other Decimal) Decimal {
+	scale := maxScale(d, other)
+	return Decimal{value: new(big.Int).Add(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+// Sub 返回 d - other，小数位数取两者较大值
+func (d Decimal) Sub(other Decimal) Decimal {
+	scale := maxScale(d, other)
+	return Decimal{value: new(big.Int).Sub(d.rescale(scale), other.rescale(scale)), scale: scale}
+}
+
+// Mul 返回 d × other，结果精确，小数位数为两者之和，超出 int32 时 panic
+func (d Decimal) Mul(other Decimal) Decimal {
+	scale := int64(d.scale) + int64(other.scale)
+	if scale > math.MaxInt32 || scale < math.MinInt32 {
+		panic("types.Decimal: 小数位数溢出")
+	}
+	return Decimal{value: new(big.Int).Mul(d.int(), other.int()), scale: int32(scale)}
+}
+
+// Div 返回 d ÷ other，保留 places 位小数并四舍五入，other 为 0 时 panic
+func (d Decimal) Div(other Decimal, places int32) Decimal {
+	if other.IsZero() {
+		panic("types.Decimal: 除数为 0")
+	}
+	if places < 0 {
+		places = 0
+	}
+	// d/other = dv×10^os / (ov×10^ds)，结果放大 10^places
+	num := new(big.Int).Mul(d.int(), pow10(other.scale+places))
+	den := new(big.Int).Mul(other.int(), pow10(d.scale))
+	return Decimal{value: roundQuo(num, den), scale: places}
+}
+
+// Round 四舍五入（远离 0）保留 places 位小数
+func (d Decimal) Round(places int32) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if places >= d.scale {
+		return Decimal{value: d.rescale(places), scale: places}
+	}
+	return Decimal{value: roundQuo(d.int(), pow10(d.scale-places)), scale: places}
+}
+
+// Truncate 直接截断保留 places 位小数
+func (d Decimal) Truncate(places int32) Decimal {
+	if places < 0 {
+		places = 0
+	}
+	if places >= d.scale {
+		return Decimal{value: d.rescale(places), scale: places}
+	}
+	return Decimal{value: new(big.Int).Quo(d.int(), pow10(d.scale-places)), scale: places}
+}
+
+// String 返回按自身小数位数格式化的十进制字符串，如 "12.50"
+func (d Decimal) String() string {
+	digits := new(big.Int).Abs(d.int()).String()
+	sign := ""
+	if d.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale == 0 {
+		return sign + digits
+	}
+	if pad := int(d.scale) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	point := len(digits) - int(d.scale)
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// StringFixed 四舍五入保留 places 位小数后格式化，如报表中的金额
+func (d Decimal) StringFixed(places int32) string {
+	return d.Round(places).String()
+}
+
+// IntPart 返回截断后的整数部分
+func (d Decimal) IntPart() int64 {
+	return d.Truncate(0).int().Int64()
+}
+
+// Float64 返回最接近的 float64，可能丢失精度
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Scan 实现 sql.Scanner，MySQL 驱动以字符串返回 DECIMAL；
+// 预处理语句中 FLOAT 列为 float32，无符号 BIGINT 可能为 uint64
+func (d *Decimal) Scan(value interface{}) error {
+	var err error
+	switch v := value.(type) {
+	case nil:
+		*d = Decimal{}
+	case []byte:
+		*d, err = ParseDecimal(string(v))
+	case string:
+		*d, err = ParseDecimal(v)
+	case int64:
+		*d = NewDecimalFromInt(v)
+	case uint64:
+		*d = Decimal{value: new(big.Int).SetUint64(v)}
+	case float32:
+		*d, err = parseFloat(float64(v), 32)
+	case float64:
+		*d, err = parseFloat(v, 64)
+	default:
+		err = fmt.Errorf("不支持的类型 %T", value)
+	}
+	if err != nil {
+		return fmt.Errorf("Decimal: %v", err)
+	}
+	return nil
+}
+
+// Value 实现 driver.Valuer，以字符串写入，保留全部精度
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// GormDataType 没有 type 标签时供 gorm 迁移使用
+func (Decimal) GormDataType() string {
+	return "decimal"
+}
+
+// MarshalJSON 序列化为 JSON 数字，与原来的 float64 字段一致
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON 解析 JSON 数字或数字字符串
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Decimal{}
+		return nil
+	}
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("Decimal: %v", err)
+		}
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return fmt.Errorf("Decimal: %v", err)
+	}
+	*d = parsed
+	return nil
+}
+
+// roundQuo 返回 num/den 四舍五入（远离 0）后的整数
+func roundQuo(num, den *big.Int) *big.Int {
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() == 0 {
+		return quo
+	}
+	rem.Abs(rem).Lsh(rem, 1)
+	if rem.Cmp(new(big.Int).Abs(den)) >= 0 {
+		if num.Sign()*den.Sign() < 0 {
+			quo.Sub(quo, big.NewInt(1))
+		} else {
+			quo.Add(quo, big.NewInt(1))
+		}
+	}
+	return quo
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+func maxScale(a, b Decimal) int32 {
+	if a.scale > b.scale {
+		return a.scale
+	}
+	return b.scale
+}
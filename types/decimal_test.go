@@ -0,0 +1,359 @@
+package types
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  string
+		scale int32
+	}{
+		{"0", "0", 0},
+		{"12.34", "12.34", 2},
+		{"12.50", "12.50", 2},
+		{"-0.05", "-0.05", 2},
+		{"+7", "7", 0},
+		{" 3.1 ", "3.1", 1},
+		{".5", "0.5", 1},
+		{"5.", "5", 0},
+		{"1e3", "1000", 0},
+		{"1.5E-2", "0.015", 3},
+		{"-2.5e1", "-25", 0},
+		{"00012.3400", "12.3400", 4},
+		{strings.Repeat("9", 62) + ".99", strings.Repeat("9", 62) + ".99", 2},
+		{strings.Repeat("9", 35) + "." + strings.Repeat("9", 30), strings.Repeat("9", 35) + "." + strings.Repeat("9", 30), 30},
+		{strings.Repeat("0", 100) + "1", "1", 0},
+		{"1e64", "1" + strings.Repeat("0", 64), 0},
+		{"1e-30", "0." + strings.Repeat("0", 29) + "1", 30},
+		{"123e-30", "0." + strings.Repeat("0", 27) + "123", 30},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.in)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q) error: %v", tt.in, err)
+			continue
+		}
+		if got := d.String(); got != tt.want || d.Scale() != tt.scale {
+			t.Errorf("ParseDecimal(%q) = %s (scale %d), want %s (scale %d)", tt.in, got, d.Scale(), tt.want, tt.scale)
+		}
+	}
+}
+
+func TestParseDecimalInvalid(t *testing.T) {
+	for _, in := range []string{"", " ", "abc", "-", "--1", "+-1", "1-2", "1.2.3", "1e", "1e1.5", "1,000", "NaN", "0x10"} {
+		if d, err := ParseDecimal(in); err == nil {
+			t.Errorf("ParseDecimal(%q) = %s, want error", in, d)
+		}
+	}
+}
+
+// 超出 DECIMAL(65,30) 的输入直接报错，不构造巨大的整数
+func TestParseDecimalRange(t *testing.T) {
+	for _, in := range []string{
+		"1e10000000",
+		"-1e2147483647",
+		"1e-2147483647",
+		"1e65",
+		"1e-31",
+		"0.1e-30",
+		strings.Repeat("9", 66),
+		"0." + strings.Repeat("0", 30) + "1",
+		strings.Repeat("9", 36) + "." + strings.Repeat("9", 30),
+		strings.Repeat("1", 100000),
+	} {
+		start := time.Now()
+		if d, err := ParseDecimal(in); err == nil {
+			t.Errorf("ParseDecimal(%.20q) = %.20s, want error", in, d)
+		}
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Errorf("ParseDecimal(%.20q) took %v", in, elapsed)
+		}
+	}
+
+	var r struct {
+		Fee Decimal `json:"fee"`
+	}
+	for _, in := range []string{`{"fee":"1e10000000"}`, `{"fee":1e-2147483647}`} {
+		if err := json.Unmarshal([]byte(in), &r); err == nil {
+			t.Errorf("Unmarshal(%s) = %s, want error", in, r.Fee)
+		}
+	}
+}
+
+func TestNewDecimal(t *testing.T) {
+	tests := []struct {
+		d    Decimal
+		want string
+	}{
+		{Decimal{}, "0"},
+		{NewDecimal(1234, 2), "12.34"},
+		{NewDecimal(-5, 3), "-0.005"},
+		{NewDecimal(12, -2), "1200"},
+		{NewDecimalFromInt(-42), "-42"},
+		{NewDecimalFromFloat(0.1), "0.1"},
+		{NewDecimalFromFloat(-1234.5678), "-1234.5678"},
+		{NewDecimalFromFloat(math.NaN()), "0"},
+		{NewDecimalFromFloat(math.Inf(1)), "0"},
+		{NewDecimalFromFloat(1e300), "0"},
+	}
+	for _, tt := range tests {
+		if got := tt.d.String(); got != tt.want {
+			t.Errorf("String() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		in     string
+		places int32
+		round  string
+		trunc  string
+	}{
+		{"2.345", 2, "2.35", "2.34"},
+		{"-2.345", 2, "-2.35", "-2.34"},
+		{"-2.344", 2, "-2.34", "-2.34"},
+		{"-2.349", 2, "-2.35", "-2.34"},
+		{"1.005", 2, "1.01", "1.00"},
+		{"0.5", 0, "1", "0"},
+		{"-0.5", 0, "-1", "0"},
+		{"-0.4", 0, "0", "0"},
+		{"0.0049", 2, "0.00", "0.00"},
+		{"1.5", 3, "1.500", "1.500"},
+		{"12.34", -1, "12", "12"},
+		{strings.Repeat("9", 62) + ".995", 2, "1" + strings.Repeat("0", 62) + ".00", strings.Repeat("9", 62) + ".99"},
+	}
+	for _, tt := range tests {
+		d := MustParseDecimal(tt.in)
+		if got := d.Round(tt.places).String(); got != tt.round {
+			t.Errorf("%s.Round(%d) = %s, want %s", tt.in, tt.places, got, tt.round)
+		}
+		if got := d.StringFixed(tt.places); got != tt.round {
+			t.Errorf("%s.StringFixed(%d) = %s, want %s", tt.in, tt.places, got, tt.round)
+		}
+		if got := d.Truncate(tt.places).String(); got != tt.trunc {
+			t.Errorf("%s.Truncate(%d) = %s, want %s", tt.in, tt.places, got, tt.trunc)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	max := strings.Repeat("9", 62) + ".99"
+	tests := []struct {
+		a, b          string
+		add, sub, mul string
+	}{
+		{"12.34", "0.66", "13.00", "11.68", "8.1444"},
+		{"1.5", "-2.25", "-0.75", "3.75", "-3.375"},
+		{"0.1", "0.2", "0.3", "-0.1", "0.02"},
+		{"-3", "0.001", "-2.999", "-3.001", "-0.003"},
+		{"0", "0", "0", "0", "0"},
+		{max, "0.01", "1" + strings.Repeat("0", 62) + ".00", strings.Repeat("9", 62) + ".98", strings.Repeat("9", 60) + ".9999"},
+		{max, max, "1" + strings.Repeat("9", 62) + ".98", "0.00", strings.Repeat("9", 63) + "8" + strings.Repeat("0", 60) + ".0001"},
+	}
+	for _, tt := range tests {
+		a, b := MustParseDecimal(tt.a), MustParseDecimal(tt.b)
+		if got := a.Add(b).String(); got != tt.add {
+			t.Errorf("%s + %s = %s, want %s", tt.a, tt.b, got, tt.add)
+		}
+		if got := a.Sub(b).String(); got != tt.sub {
+			t.Errorf("%s - %s = %s, want %s", tt.a, tt.b, got, tt.sub)
+		}
+		if got := a.Mul(b).String(); got != tt.mul {
+			t.Errorf("%s × %s = %s, want %s", tt.a, tt.b, got, tt.mul)
+		}
+	}
+
+	// 小数位数之和超出 int32 时 panic，而不是回绕为负数
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Mul with scale overflow did not panic")
+			}
+		}()
+		NewDecimal(1, math.MaxInt32).Mul(NewDecimal(1, 1))
+	}()
+
+	// 运算不修改接收者
+	a := MustParseDecimal("1.25")
+	a.Add(a).Neg()
+	if a.String() != "1.25" {
+		t.Errorf("receiver changed to %s", a)
+	}
+}
+
+func TestDecimalDiv(t *testing.T) {
+	tests := []struct {
+		a, b   string
+		places int32
+		want   string
+	}{
+		{"1", "3", 4, "0.3333"},
+		{"2", "3", 2, "0.67"},
+		{"-2", "3", 2, "-0.67"},
+		{"2", "-3", 2, "-0.67"},
+		{"-2", "-3", 2, "0.67"},
+		{"1", "8", 2, "0.13"},
+		{"-1", "8", 2, "-0.13"},
+		{"1", "-8", 2, "-0.13"},
+		{"12.34", "0.5", 2, "24.68"},
+		{"0.001", "1000", 4, "0.0000"},
+		{"10", "4", -1, "3"},
+		{"1" + strings.Repeat("0", 62) + ".00", "3", 2, strings.Repeat("3", 62) + ".33"},
+	}
+	for _, tt := range tests {
+		if got := MustParseDecimal(tt.a).Div(MustParseDecimal(tt.b), tt.places).String(); got != tt.want {
+			t.Errorf("%s ÷ %s (%d) = %s, want %s", tt.a, tt.b, tt.places, got, tt.want)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Div by zero did not panic")
+		}
+	}()
+	NewDecimalFromInt(1).Div(Decimal{}, 2)
+}
+
+func TestDecimalCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		cmp  int
+	}{
+		{"1.50", "1.5", 0},
+		{"0", "-0.00", 0},
+		{"-1", "0.001", -1},
+		{"10", "9.999", 1},
+		{strings.Repeat("9", 62) + ".99", strings.Repeat("9", 62) + ".98", 1},
+	}
+	for _, tt := range tests {
+		a, b := MustParseDecimal(tt.a), MustParseDecimal(tt.b)
+		if got := a.Cmp(b); got != tt.cmp {
+			t.Errorf("%s.Cmp(%s) = %d, want %d", tt.a, tt.b, got, tt.cmp)
+		}
+		if got := a.Equal(b); got != (tt.cmp == 0) {
+			t.Errorf("%s.Equal(%s) = %v", tt.a, tt.b, got)
+		}
+	}
+
+	d := MustParseDecimal("-2.75")
+	if d.Sign() != -1 || d.IsZero() || !(Decimal{}).IsZero() {
+		t.Errorf("Sign/IsZero wrong for %s", d)
+	}
+	if got := d.Abs().String(); got != "2.75" {
+		t.Errorf("Abs() = %s", got)
+	}
+	if got := d.IntPart(); got != -2 {
+		t.Errorf("IntPart() = %d", got)
+	}
+	if got := d.Float64(); got != -2.75 {
+		t.Errorf("Float64() = %v", got)
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	type row struct {
+		Fee Decimal `json:"fee"`
+	}
+	tests := []struct {
+		in   string
+		fee  string
+		json string
+	}{
+		{`{"fee":12.50}`, "12.50", `{"fee":12.50}`},
+		{`{"fee":"-0.05"}`, "-0.05", `{"fee":-0.05}`},
+		{`{"fee":1e2}`, "100", `{"fee":100}`},
+		{`{"fee":null}`, "0", `{"fee":0}`},
+		{`{}`, "0", `{"fee":0}`},
+		{`{"fee":` + strings.Repeat("9", 62) + `.99}`, strings.Repeat("9", 62) + ".99", `{"fee":` + strings.Repeat("9", 62) + `.99}`},
+	}
+	for _, tt := range tests {
+		var r row
+		if err := json.Unmarshal([]byte(tt.in), &r); err != nil {
+			t.Errorf("Unmarshal(%s) error: %v", tt.in, err)
+			continue
+		}
+		if got := r.Fee.String(); got != tt.fee {
+			t.Errorf("Unmarshal(%s) = %s, want %s", tt.in, got, tt.fee)
+		}
+		data, err := json.Marshal(r)
+		if err != nil {
+			t.Errorf("Marshal(%s) error: %v", tt.fee, err)
+			continue
+		}
+		if string(data) != tt.json {
+			t.Errorf("Marshal(%s) = %s, want %s", tt.fee, data, tt.json)
+		}
+	}
+
+	for _, in := range []string{`{"fee":"abc"}`, `{"fee":true}`, `{"fee":"1`} {
+		var r row
+		if err := json.Unmarshal([]byte(in), &r); err == nil {
+			t.Errorf("Unmarshal(%s) = %s, want error", in, r.Fee)
+		}
+	}
+}
+
+func TestDecimalScan(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{nil, "0"},
+		{[]byte("12.34"), "12.34"},
+		{[]byte(strings.Repeat("9", 62) + ".99"), strings.Repeat("9", 62) + ".99"},
+		{"-0.50", "-0.50"},
+		{int64(-42), "-42"},
+		{uint64(math.MaxUint64), "18446744073709551615"},
+		{float32(0.1), "0.1"},
+		{float32(-3.7), "-3.7"},
+		{float32(1234.5), "1234.5"},
+		{float64(0.1), "0.1"},
+		{float64(-1234.5678), "-1234.5678"},
+	}
+	for _, tt := range tests {
+		d := MustParseDecimal("99.9")
+		if err := d.Scan(tt.in); err != nil {
+			t.Errorf("Scan(%T %v) error: %v", tt.in, tt.in, err)
+			continue
+		}
+		if got := d.String(); got != tt.want {
+			t.Errorf("Scan(%T %v) = %s, want %s", tt.in, tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []interface{}{true, int32(1), []byte("1.2.3"), "abc", []byte("1e100"), float64(1e300), math.Inf(-1), float32(math.NaN())} {
+		var d Decimal
+		if err := d.Scan(in); err == nil {
+			t.Errorf("Scan(%T %v) = %s, want error", in, in, d)
+		}
+	}
+}
+
+func TestDecimalValue(t *testing.T) {
+	for _, in := range []string{"0", "12.50", "-0.05", strings.Repeat("9", 62) + ".99"} {
+		v, err := MustParseDecimal(in).Value()
+		if err != nil {
+			t.Errorf("Value(%s) error: %v", in, err)
+			continue
+		}
+		if v != in {
+			t.Errorf("Value(%s) = %v (%T), want string %s", in, v, v, in)
+		}
+
+		// 写入的值再读回不变
+		var d Decimal
+		if err := d.Scan([]byte(v.(string))); err != nil || d.String() != in {
+			t.Errorf("round trip %s = %s, %v", in, d, err)
+		}
+	}
+	if v, _ := (Decimal{}).Value(); v != "0" {
+		t.Errorf("zero Value() = %v", v)
+	}
+}
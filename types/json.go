@@ -0,0 +1,62 @@
+package types
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// JSON 以 JSON 文本存储在 text/varchar 列中的值，Data 为解析后的 Go 值
+// 空字符串和 NULL 解析为 T 的零值，写入时序列化为 JSON 文本
+type JSON[T any] struct {
+	Data T
+}
+
+// NewJSON 创建 JSON
+func NewJSON[T any](data T) JSON[T] {
+	return JSON[T]{Data: data}
+}
+
+// Scan 实现 sql.Scanner
+func (j *JSON[T]) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("JSON: 不支持的类型 %T", value)
+	}
+
+	var zero T
+	j.Data = zero
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, &j.Data); err != nil {
+		return fmt.Errorf("JSON: %v", err)
+	}
+	return nil
+}
+
+// Value 实现 driver.Valuer，写入 JSON 文本
+func (j JSON[T]) Value() (driver.Value, error) {
+	data, err := json.Marshal(j.Data)
+	if err != nil {
+		return nil, fmt.Errorf("JSON: %v", err)
+	}
+	return string(data), nil
+}
+
+// MarshalJSON 直接序列化 Data，而不是嵌套的 JSON 字符串
+func (j JSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(j.Data)
+}
+
+// UnmarshalJSON 解析到 Data
+func (j *JSON[T]) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &j.Data)
+}
@@ -0,0 +1,211 @@
+// Package types 生成的模型中使用的自定义字段类型
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UnixTime 以整数秒存储的时间，对应 int(11) 等整数列
+// 读写数据库时保持 Unix 时间戳格式，0 对应零值时间
+type UnixTime struct {
+	time.Time
+}
+
+// NewUnixTime 创建 UnixTime
+func NewUnixTime(t time.Time) UnixTime {
+	return UnixTime{Time: t}
+}
+
+// Unix 返回 Unix 时间戳，零值时间返回 0
+func (t UnixTime) Unix() int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Time.Unix()
+}
+
+// Scan 实现 sql.Scanner
+func (t *UnixTime) Scan(value interface{}) error {
+	sec, err := scanUnix(value)
+	if err != nil {
+		return fmt.Errorf("UnixTime: %v", err)
+	}
+	t.Time = fromUnix(sec)
+	return nil
+}
+
+// Value 实现 driver.Valuer，写入整数秒
+func (t UnixTime) Value() (driver.Value, error) {
+	return t.Unix(), nil
+}
+
+// MarshalJSON 序列化为整数秒，与原来的 int 字段一致
+func (t UnixTime) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatInt(t.Unix(), 10)), nil
+}
+
+// UnmarshalJSON 解析整数秒、数字字符串或 RFC 3339 时间字符串
+func (t *UnixTime) UnmarshalJSON(data []byte) error {
+	parsed, err := unmarshalUnixJSON(data)
+	if err != nil {
+		return fmt.Errorf("UnixTime: %v", err)
+	}
+	t.Time = parsed
+	return nil
+}
+
+// MarshalText 实现 encoding.TextMarshaler，与 JSON 相同输出整数秒
+// 不定义时会使用内嵌 time.Time 的 RFC 3339 格式
+func (t UnixTime) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatInt(t.Unix(), 10)), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler，解析整数秒或 RFC 3339 时间字符串
+func (t *UnixTime) UnmarshalText(text []byte) error {
+	parsed, err := parseUnixText(string(text))
+	if err != nil {
+		return fmt.Errorf("UnixTime: %v", err)
+	}
+	t.Time = parsed
+	return nil
+}
+
+// UnixTimeString 以十进制字符串存储的 Unix 时间，对应 char(10) 等字符串列
+// 读写数据库时保持字符串格式，"0" 或空字符串对应零值时间
+type UnixTimeString struct {
+	time.Time
+}
+
+// NewUnixTimeString 创建 UnixTimeString
+func NewUnixTimeString(t time.Time) UnixTimeString {
+	return UnixTimeString{Time: t}
+}
+
+// Unix 返回 Unix 时间戳，零值时间返回 0
+func (t UnixTimeString) Unix() int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Time.Unix()
+}
+
+// Scan 实现 sql.Scanner
+func (t *UnixTimeString) Scan(value interface{}) error {
+	sec, err := scanUnix(value)
+	if err != nil {
+		return fmt.Errorf("UnixTimeString: %v", err)
+	}
+	t.Time = fromUnix(sec)
+	return nil
+}
+
+// Value 实现 driver.Valuer，写入十进制字符串
+func (t UnixTimeString) Value() (driver.Value, error) {
+	return strconv.FormatInt(t.Unix(), 10), nil
+}
+
+// MarshalJSON 序列化为数字字符串，与原来的 string 字段一致
+func (t UnixTimeString) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(strconv.FormatInt(t.Unix(), 10))), nil
+}
+
+// UnmarshalJSON 解析整数秒、数字字符串或 RFC 3339 时间字符串
+func (t *UnixTimeString) UnmarshalJSON(data []byte) error {
+	parsed, err := unmarshalUnixJSON(data)
+	if err != nil {
+		return fmt.Errorf("UnixTimeString: %v", err)
+	}
+	t.Time = parsed
+	return nil
+}
+
+// MarshalText 实现 encoding.TextMarshaler，与 JSON 相同输出整数秒
+// 不定义时会使用内嵌 time.Time 的 RFC 3339 格式
+func (t UnixTimeString) MarshalText() ([]byte, error) {
+	return []byte(strconv.FormatInt(t.Unix(), 10)), nil
+}
+
+// UnmarshalText 实现 encoding.TextUnmarshaler，解析整数秒或 RFC 3339 时间字符串
+func (t *UnixTimeString) UnmarshalText(text []byte) error {
+	parsed, err := parseUnixText(string(text))
+	if err != nil {
+		return fmt.Errorf("UnixTimeString: %v", err)
+	}
+	t.Time = parsed
+	return nil
+}
+
+// scanUnix 从数据库值中读取 Unix 时间戳
+func scanUnix(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case int64:
+		return v, nil
+	case uint64:
+		// BIGINT UNSIGNED 列
+		if v > math.MaxInt64 {
+			return 0, fmt.Errorf("时间戳 %d 超出范围", v)
+		}
+		return int64(v), nil
+	case []byte:
+		return parseUnix(string(v))
+	case string:
+		return parseUnix(v)
+	case time.Time:
+		return v.Unix(), nil
+	default:
+		return 0, fmt.Errorf("不支持的类型 %T", value)
+	}
+}
+
+// parseUnix 解析十进制时间戳，空字符串视为 0
+func parseUnix(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// fromUnix 时间戳 0 返回零值时间，便于用 IsZero 判断未设置
+func fromUnix(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// unmarshalUnixJSON 解析 JSON 中的时间戳或时间字符串
+func unmarshalUnixJSON(data []byte) (time.Time, error) {
+	if string(data) == "null" {
+		return time.Time{}, nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		s = string(data)
+	}
+	parsed, err := parseUnixText(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("无效的时间 %s", data)
+	}
+	return parsed, nil
+}
+
+// parseUnixText 解析十进制时间戳或 RFC 3339 时间字符串，空字符串和 0 返回零值时间
+func parseUnixText(s string) (time.Time, error) {
+	if sec, err := parseUnix(s); err == nil {
+		return fromUnix(sec), nil
+	}
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("无效的时间 %q", s)
+	}
+	return parsed, nil
+}
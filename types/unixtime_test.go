@@ -0,0 +1,144 @@
+package types
+
+import (
+	"encoding"
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	_ encoding.TextMarshaler   = UnixTime{}
+	_ encoding.TextUnmarshaler = (*UnixTime)(nil)
+	_ encoding.TextMarshaler   = UnixTimeString{}
+	_ encoding.TextUnmarshaler = (*UnixTimeString)(nil)
+)
+
+func TestUnixTimeText(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	tests := []struct {
+		value time.Time
+		want  string
+	}{
+		{at, "1700000000"},
+		{time.Time{}, "0"},
+	}
+	for _, tt := range tests {
+		for _, m := range []encoding.TextMarshaler{NewUnixTime(tt.value), NewUnixTimeString(tt.value)} {
+			got, err := m.MarshalText()
+			if err != nil || string(got) != tt.want {
+				t.Errorf("%T(%v).MarshalText() = %s, %v, want %s", m, tt.value, got, err, tt.want)
+			}
+		}
+	}
+
+	parse := []struct {
+		in   string
+		want time.Time
+	}{
+		{"1700000000", at},
+		{" 1700000000 ", at},
+		{"0", time.Time{}},
+		{"", time.Time{}},
+		{"2023-11-14T22:13:20Z", at},
+	}
+	for _, tt := range parse {
+		var u UnixTime
+		if err := u.UnmarshalText([]byte(tt.in)); err != nil || !u.Equal(tt.want) {
+			t.Errorf("UnixTime.UnmarshalText(%q) = %v, %v, want %v", tt.in, u.Time, err, tt.want)
+		}
+		var s UnixTimeString
+		if err := s.UnmarshalText([]byte(tt.in)); err != nil || !s.Equal(tt.want) {
+			t.Errorf("UnixTimeString.UnmarshalText(%q) = %v, %v, want %v", tt.in, s.Time, err, tt.want)
+		}
+	}
+	for _, in := range []string{"abc", "1.5", "2023-11-14"} {
+		var u UnixTime
+		if err := u.UnmarshalText([]byte(in)); err == nil {
+			t.Errorf("UnixTime.UnmarshalText(%q) 应返回错误", in)
+		}
+	}
+}
+
+// TestUnixTimeYAML yaml 使用 MarshalText/UnmarshalText
+func TestUnixTimeYAML(t *testing.T) {
+	type row struct {
+		Created UnixTime       `yaml:"created"`
+		Updated UnixTimeString `yaml:"updated"`
+	}
+	at := time.Unix(1700000000, 0)
+	data, err := yaml.Marshal(row{NewUnixTime(at), NewUnixTimeString(time.Time{})})
+	if err != nil || string(data) != "created: \"1700000000\"\nupdated: \"0\"\n" {
+		t.Fatalf("yaml.Marshal = %q, %v", data, err)
+	}
+	var got row
+	if err := yaml.Unmarshal(data, &got); err != nil || !got.Created.Equal(at) || !got.Updated.IsZero() {
+		t.Errorf("yaml.Unmarshal = %+v, %v", got, err)
+	}
+}
+
+func TestUnixTimeJSON(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	u, _ := json.Marshal(NewUnixTime(at))
+	s, _ := json.Marshal(NewUnixTimeString(at))
+	if string(u) != "1700000000" || string(s) != `"1700000000"` {
+		t.Errorf("json.Marshal = %s, %s", u, s)
+	}
+	for _, in := range []string{`1700000000`, `"1700000000"`, `"2023-11-14T22:13:20Z"`} {
+		var v UnixTime
+		if err := json.Unmarshal([]byte(in), &v); err != nil || !v.Equal(at) {
+			t.Errorf("json.Unmarshal(%s) = %v, %v", in, v.Time, err)
+		}
+	}
+	var v UnixTime
+	if err := json.Unmarshal([]byte("null"), &v); err != nil || !v.IsZero() {
+		t.Errorf("json.Unmarshal(null) = %v, %v", v.Time, err)
+	}
+}
+
+func TestUnixTimeScan(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	tests := []struct {
+		value interface{}
+		want  time.Time
+	}{
+		{nil, time.Time{}},
+		{int64(1700000000), at},
+		{uint64(1700000000), at},
+		{int64(0), time.Time{}},
+		{[]byte("1700000000"), at},
+		{"1700000000", at},
+		{"", time.Time{}},
+		{at, at},
+	}
+	for _, tt := range tests {
+		var u UnixTime
+		if err := u.Scan(tt.value); err != nil || !u.Equal(tt.want) {
+			t.Errorf("UnixTime.Scan(%#v) = %v, %v, want %v", tt.value, u.Time, err, tt.want)
+		}
+		var s UnixTimeString
+		if err := s.Scan(tt.value); err != nil || !s.Equal(tt.want) {
+			t.Errorf("UnixTimeString.Scan(%#v) = %v, %v, want %v", tt.value, s.Time, err, tt.want)
+		}
+	}
+
+	for _, value := range []interface{}{uint64(math.MaxUint64), "abc", 1.5} {
+		var u UnixTime
+		if err := u.Scan(value); err == nil {
+			t.Errorf("UnixTime.Scan(%#v) 应返回错误", value)
+		}
+	}
+}
+
+func TestUnixTimeValue(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	if v, err := NewUnixTime(at).Value(); err != nil || v != int64(1700000000) {
+		t.Errorf("UnixTime.Value() = %#v, %v", v, err)
+	}
+	if v, err := NewUnixTimeString(time.Time{}).Value(); err != nil || v != "0" {
+		t.Errorf("UnixTimeString.Value() = %#v, %v", v, err)
+	}
+}